@@ -14,7 +14,23 @@ type AsyncCollector struct {
 	waitCount int
 	tasks     []*collectorTask
 	results   []TaskResult
+	completed []bool
 	resChan   chan *collectorResult
+	failures  int
+	failErr   error
+
+	// closed is set once a collector built on the AsyncCollector has made its
+	// decision, after which new tasks are never run since nothing would
+	// collect their results
+	closed bool
+
+	queueLock sync.Mutex
+	running   int
+	queued    []*collectorTask
+
+	// tasksLock guards changes to tasks so they can be listed without
+	// waiting for lock, which is held while waiting for results
+	tasksLock sync.RWMutex
 }
 
 // NewAsyncCollector creates a new AsyncCollector instance
@@ -27,29 +43,180 @@ func NewAsyncCollector(configs ...TaskConfig) *AsyncCollector {
 		waitCount: 0,
 		tasks:     make([]*collectorTask, 0),
 		results:   make([]TaskResult, 0),
+		completed: make([]bool, 0),
 		resChan:   make(chan *collectorResult),
 	}
 }
 
+// cleanup waits for all remaining tasks to finish and calls closer on every
+// result the collector has received. If closer is nil the remaining results
+// are discarded.
 func (c *AsyncCollector) cleanup(closer CollectorCloser) {
-	for _, res := range c.results {
-		if res != nil {
-			closer(res)
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if closer != nil {
+		for _, res := range c.results {
+			if res != nil {
+				closer(res)
+			}
 		}
 	}
 
-	for {
+	for c.waitCount > 0 {
 		res := <-c.resChan
-		closer(res.Result)
-
-		c.lock.Lock()
 		c.waitCount--
-		if c.waitCount == 0 {
-			return
+
+		if closer != nil {
+			closer(res.Result)
+		}
+	}
+}
+
+// abort cancels the context of every task whose result hasn't been received,
+// skipping queued tasks so they never start, and then cleans up the results of
+// all tasks using closer.
+func (c *AsyncCollector) abort(closer CollectorCloser) {
+	c.waitCount -= c.dropQueued()
+
+	for idx, colTask := range c.tasks {
+		if !c.completed[idx] {
+			colTask.task.cancelCtx()
+		}
+	}
+
+	go c.cleanup(closer)
+}
+
+// abandon stops waiting for the collector's tasks after a timeout. Tasks that
+// were never started are skipped with ErrCancelled, and the results of tasks
+// still executing are passed to closer if one is provided.
+func (c *AsyncCollector) abandon(closer CollectorCloser) {
+	c.waitCount -= c.dropQueued()
+
+	if closer != nil && c.waitCount > 0 {
+		go c.cleanup(closer)
+	}
+}
+
+// collect receives task results until all tasks have finished, the timeout
+// elapses or ctx is done. If onResult is not nil it's called with each result
+// as it's received and collect returns early if it returns true. The
+// collector's lock must be held when calling collect.
+func (c *AsyncCollector) collect(ctx context.Context, timeout time.Duration, onResult func(*collectorResult) bool) error {
+	var timeoutChan <-chan time.Time
+	if timeout > 0 {
+		timeoutChan = c.cfg.clock.After(timeout)
+	} else {
+		timeoutChan = make(chan time.Time)
+	}
+
+	for c.waitCount > 0 {
+		select {
+		case res := <-c.resChan:
+			c.results[res.Choice] = res.Result
+			c.completed[res.Choice] = true
+			c.waitCount--
+
+			if onResult != nil && onResult(res) {
+				return nil
+			}
+		case <-timeoutChan:
+			c.timedOut()
+			return ErrTimeout
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+// timedOut notifies the collector's hooks of the tasks that are still
+// outstanding after a timeout.
+func (c *AsyncCollector) timedOut() {
+	c.cfg.collectorTimeout(c.waitCount)
+
+	for idx, colTask := range c.tasks {
+		if !c.completed[idx] {
+			colTask.task.cfg.eachHook(func(h Hooks) { h.OnTimeout(colTask.task) })
+		}
+	}
+}
+
+// checkFailure returns true if the collector has a fail policy and the result
+// causes it to fail, recording the result's error as the collector's error.
+func (c *AsyncCollector) checkFailure(res *collectorResult) bool {
+	if c.cfg.failPolicy == nil || res.Result == nil || res.Result.Err() == nil {
+		return false
+	}
+
+	c.failures++
+	if !c.cfg.failPolicy(res.Result.Err(), c.failures) {
+		return false
+	}
+
+	c.failErr = res.Result.Err()
+	return true
+}
+
+// schedule starts the task right away if the collector is below its maximum
+// concurrency, otherwise it's queued until a running task finishes.
+func (c *AsyncCollector) schedule(colTask *collectorTask) {
+	c.queueLock.Lock()
+	defer c.queueLock.Unlock()
+
+	if c.cfg.maxConcurrency > 0 && c.running >= c.cfg.maxConcurrency {
+		c.queued = append(c.queued, colTask)
+		return
+	}
+
+	c.running++
+	colTask.Start()
+}
+
+// taskDone is called when a task finishes executing to free its slot and start
+// the next queued task, if there is one.
+func (c *AsyncCollector) taskDone() {
+	c.queueLock.Lock()
+	defer c.queueLock.Unlock()
+
+	c.running--
+	if len(c.queued) == 0 {
+		return
+	}
+
+	next := c.queued[0]
+	c.queued[0] = nil
+	c.queued = c.queued[1:]
+
+	c.running++
+	next.Start()
+}
+
+// dropQueued removes all tasks which haven't been started yet from the queue
+// and skips them with ErrCancelled so they finish without ever starting. Their
+// results are recorded as completed so waiting again returns the error. It
+// returns the number of tasks that were dropped. The collector's lock must be
+// held when calling dropQueued.
+func (c *AsyncCollector) dropQueued() int {
+	c.queueLock.Lock()
+	defer c.queueLock.Unlock()
+
+	dropped := len(c.queued)
+	for _, colTask := range c.queued {
+		colTask.task.cancelCtx()
+		colTask.task.schedule()
+		if colTask.task.skip(NewErrorResult(ErrCancelled)) {
+			// Receive the result so the task's goroutine can exit
+			res, _ := colTask.task.Wait(0)
+			c.results[colTask.choice] = res
+			c.completed[colTask.choice] = true
 		}
-		c.lock.Unlock()
 	}
+	c.queued = nil
 
+	return dropped
 }
 
 // Run takes a TaskFunc to execute and zero or more parameters to pass to that
@@ -60,16 +227,43 @@ func (c *AsyncCollector) Run(f TaskFunc, args ...interface{}) {
 
 // RunWithContext calls Run and uses the provided context.Context to run the task.
 func (c *AsyncCollector) RunWithContext(ctx context.Context, f TaskFunc, args ...interface{}) {
+	c.RunWithConfig(ctx, nil, f, args...)
+}
+
+// RunWithConfig calls RunWithContext and applies the configs to this task only,
+// on top of the collector's configuration.
+func (c *AsyncCollector) RunWithConfig(ctx context.Context, configs []TaskConfig, f TaskFunc, args ...interface{}) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
-	task := newTask(ctx, c.cfg, f, args...)
+	if c.closed {
+		return
+	}
+
+	task := newTask(ctx, c.cfg.with(configs), f, args...)
 
-	colTask := newCollectorTask(task, c.resChan)
+	colTask := newCollectorTask(task, len(c.results), c.resChan)
+	colTask.done = c.taskDone
+	c.tasksLock.Lock()
 	c.tasks = append(c.tasks, colTask)
+	c.tasksLock.Unlock()
 	c.results = append(c.results, nil)
+	c.completed = append(c.completed, false)
 	c.waitCount++
-	colTask.Start(len(c.results) - 1)
+	c.schedule(colTask)
+}
+
+// Tasks returns every task added to the collector, in the order they were added.
+func (c *AsyncCollector) Tasks() []*Task {
+	c.tasksLock.RLock()
+	defer c.tasksLock.RUnlock()
+
+	tasks := make([]*Task, 0, len(c.tasks))
+	for _, colTask := range c.tasks {
+		tasks = append(tasks, colTask.task)
+	}
+
+	return tasks
 }
 
 // Wait will wait until all tasks associated with the Collector have finished and then
@@ -82,37 +276,174 @@ func (c *AsyncCollector) Wait(timeout time.Duration) ([]TaskResult, error) {
 
 // WaitCloser will wait similar to Wait except if an error occurs while waiting
 // for tasks to finish, closer will be called on each task result as they finish.
+// Tasks queued due to WithMaxConcurrency that haven't started by the time an error
+// occurs are never started and have their contexts cancelled.
+//
+// If the collector was created using WithFailFast and a task's result trips the
+// fail policy, the contexts of all other tasks are cancelled and WaitCloser returns
+// the error from that result. The results of every task, including those that finish
+// after being cancelled, are passed to closer.
 func (c *AsyncCollector) WaitCloser(timeout time.Duration, closer CollectorCloser) ([]TaskResult, error) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
+	if c.failErr != nil {
+		return nil, c.failErr
+	}
+
 	if c.waitCount == 0 {
 		return c.results, nil
 	}
 
-	var timeoutChan <-chan time.Time
-	if timeout > 0 {
-		timeoutChan = c.cfg.clock.After(timeout)
-	} else {
-		timeoutChan = make(chan time.Time)
+	err := c.collect(context.Background(), timeout, c.checkFailure)
+	if err == ErrTimeout {
+		c.abandon(closer)
+		return nil, ErrTimeout
 	}
 
-	for {
-		select {
-		case res := <-c.resChan:
-			c.results[res.Choice] = res.Result
-			c.waitCount--
+	if c.failErr != nil {
+		c.abort(closer)
+		return nil, c.failErr
+	}
+
+	return c.results, nil
+}
+
+// WaitPartial will wait similar to Wait except if the timeout elapses before all
+// tasks have finished, the results of the tasks that did finish are returned along
+// with a *TimeoutError listing the indices of the tasks that are still outstanding.
+// The returned statuses give the state of each task at the time WaitPartial returned.
+// Outstanding tasks continue to execute and can be collected by calling one of the
+// Wait methods again. Since the error isn't ErrTimeout itself, use IsTimeout to
+// check whether WaitPartial timed out.
+func (c *AsyncCollector) WaitPartial(timeout time.Duration) ([]TaskResult, []ResultStatus, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.failErr != nil {
+		return nil, nil, c.failErr
+	}
 
-			if c.waitCount == 0 {
-				return c.results, nil
+	err := c.collect(context.Background(), timeout, c.checkFailure)
+
+	results := make([]TaskResult, len(c.results))
+	copy(results, c.results)
+	statuses := c.statuses()
+
+	if c.failErr != nil {
+		c.abort(nil)
+		return results, statuses, c.failErr
+	}
+
+	if err == ErrTimeout {
+		outstanding := make([]int, 0, c.waitCount)
+		for idx, status := range statuses {
+			if status != ResultCompleted {
+				outstanding = append(outstanding, idx)
 			}
-		case <-timeoutChan:
-			if closer != nil {
-				go c.cleanup(closer)
+		}
+		return results, statuses, &TimeoutError{Outstanding: outstanding}
+	}
+
+	return results, statuses, nil
+}
+
+// Stream returns a channel that receives the result of each task in the order
+// the tasks finish. Results the collector has already received are sent first.
+// The channel is closed once all tasks have finished or the timeout elapses. If
+// timeout is 0, Stream will wait indefinitely for tasks to finish. Results of
+// tasks that are still executing when the channel is closed can be collected
+// by calling one of the Wait methods. If the collector's fail policy is tripped,
+// the channel is closed after the failing result is sent and the remaining tasks
+// are cancelled.
+func (c *AsyncCollector) Stream(timeout time.Duration) <-chan IndexedResult {
+	return c.StreamWithContext(context.Background(), timeout)
+}
+
+// StreamWithContext calls Stream and will also close the channel when ctx is done.
+func (c *AsyncCollector) StreamWithContext(ctx context.Context, timeout time.Duration) <-chan IndexedResult {
+	c.lock.Lock()
+
+	// The channel is buffered to hold every result so sending never blocks,
+	// even if the receiver stops reading.
+	resChan := make(chan IndexedResult, len(c.tasks))
+
+	go func() {
+		defer c.lock.Unlock()
+		defer close(resChan)
+
+		if c.failErr != nil {
+			return
+		}
+
+		for idx, completed := range c.completed {
+			if completed {
+				resChan <- IndexedResult{Index: idx, Result: c.results[idx]}
 			}
-			return nil, ErrTimeout
+		}
+
+		c.collect(ctx, timeout, func(res *collectorResult) bool {
+			resChan <- IndexedResult{Index: res.Choice, Result: res.Result}
+			return c.checkFailure(res)
+		})
+
+		if c.failErr != nil {
+			c.abort(nil)
+		}
+	}()
+
+	return resChan
+}
+
+// IndexedResult is the result of a task along with the index of the task in
+// the collector it was run with.
+type IndexedResult struct {
+	Index  int
+	Result TaskResult
+}
+
+// statuses returns the current ResultStatus of each task in the collector
+func (c *AsyncCollector) statuses() []ResultStatus {
+	statuses := make([]ResultStatus, len(c.tasks))
+	for idx, colTask := range c.tasks {
+		if c.completed[idx] {
+			statuses[idx] = ResultCompleted
+			continue
+		}
+
+		select {
+		case <-colTask.task.Started():
+			statuses[idx] = ResultRunning
+		default:
+			statuses[idx] = ResultNotStarted
 		}
 	}
+	return statuses
+}
+
+// ResultStatus is the status of a task's result within a collector
+type ResultStatus int
+
+const (
+	// ResultNotStarted means the task has not started executing
+	ResultNotStarted ResultStatus = iota
+	// ResultRunning means the task has started but its result has not been received
+	ResultRunning
+	// ResultCompleted means the task's result has been received by the collector
+	ResultCompleted
+)
+
+func (s ResultStatus) String() string {
+	switch s {
+	case ResultNotStarted:
+		return "not started"
+	case ResultRunning:
+		return "running"
+	case ResultCompleted:
+		return "completed"
+	default:
+		return "unknown"
+	}
 }
 
 type collectorResult struct {
@@ -122,25 +453,37 @@ type collectorResult struct {
 
 type collectorTask struct {
 	task    *Task
+	choice  int
 	closer  CollectorCloser
+	done    func()
 	resChan chan<- *collectorResult
 }
 
-func newCollectorTask(task *Task, resChan chan<- *collectorResult) *collectorTask {
+func newCollectorTask(task *Task, choice int, resChan chan<- *collectorResult) *collectorTask {
 	return &collectorTask{
 		task:    task,
+		choice:  choice,
 		resChan: resChan,
 	}
 }
 
-func (ct *collectorTask) Start(choice int) {
-	go ct.worker(choice)
+func (ct *collectorTask) Start() {
+	go ct.worker()
 }
 
-func (ct *collectorTask) worker(choice int) {
+func (ct *collectorTask) worker() {
+	if ct.task.cfg.rateLimiter != nil {
+		ct.task.schedule()
+		ct.task.startLimited()
+	}
+
 	res, _ := ct.task.StartSync()
+	if ct.done != nil {
+		ct.done()
+	}
+
 	ct.resChan <- &collectorResult{
-		Choice: choice,
+		Choice: ct.choice,
 		Result: res,
 	}
 }
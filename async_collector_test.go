@@ -1,6 +1,8 @@
 package boom
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -232,3 +234,407 @@ func (s *AsyncColSuite) TestArgs(t sweet.T) {
 	Expect(res[1]).To(Equal(&ValueResult{Value: "r 3 4", Error: nil}))
 	Expect(res[2]).To(Equal(&ValueResult{Value: "r 5 6", Error: nil}))
 }
+
+func (s *AsyncColSuite) TestMaxConcurrency(t sweet.T) {
+	col := NewAsyncCollector(WithMaxConcurrency(2))
+
+	var lock sync.Mutex
+	running := 0
+	maxRunning := 0
+	release := make(chan struct{})
+
+	f := func(task *Task, data ...interface{}) TaskResult {
+		lock.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		lock.Unlock()
+
+		<-release
+
+		lock.Lock()
+		running--
+		lock.Unlock()
+
+		return NewValueResult(data[0], nil)
+	}
+
+	for i := 0; i < 6; i++ {
+		col.Run(f, i)
+	}
+
+	Eventually(func() int {
+		lock.Lock()
+		defer lock.Unlock()
+		return running
+	}).Should(Equal(2))
+	close(release)
+
+	res, err := col.Wait(0)
+	Expect(err).To(BeNil())
+	Expect(res).To(HaveLen(6))
+	for i, r := range res {
+		Expect(r).To(Equal(&ValueResult{Value: i, Error: nil}))
+	}
+	Expect(maxRunning).To(Equal(2))
+}
+
+func (s *AsyncColSuite) TestMaxConcurrencyTimeoutQueued(t sweet.T) {
+	clock := glock.NewMockClock()
+
+	col := NewAsyncCollector(WithClock(clock), WithMaxConcurrency(1))
+
+	var running sync.WaitGroup
+	running.Add(1)
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		running.Done()
+		clock.Sleep(20 * time.Millisecond)
+		return NewValueResult(1, nil)
+	})
+
+	secondStarted := make(chan struct{})
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		close(secondStarted)
+		return NewValueResult(2, nil)
+	})
+	secondTask := col.tasks[1].task
+
+	running.Wait()
+
+	closed := make(chan TaskResult, 2)
+	go clock.BlockingAdvance(10 * time.Millisecond)
+	res, err := col.WaitCloser(10*time.Millisecond, func(res TaskResult) {
+		closed <- res
+	})
+	Expect(res).To(BeNil())
+	Expect(err).To(Equal(ErrTimeout))
+	Expect(secondTask.Stopping()).To(BeClosed())
+
+	go clock.BlockingAdvance(10 * time.Millisecond)
+
+	Eventually(closed).Should(Receive(Equal(NewErrorResult(ErrCancelled))))
+	Eventually(closed).Should(Receive(Equal(&ValueResult{Value: 1, Error: nil})))
+	Consistently(closed).ShouldNot(Receive())
+	Expect(secondStarted).ToNot(BeClosed())
+	Expect(secondTask.Finished()).To(BeClosed())
+
+	// The task that never started has an error result when waiting again
+	res, err = col.Wait(0)
+	Expect(err).To(BeNil())
+	Expect(res).To(HaveLen(2))
+	Expect(res[1]).To(Equal(NewErrorResult(ErrCancelled)))
+}
+
+func (s *AsyncColSuite) TestMaxConcurrencyTimeoutWaitAgain(t sweet.T) {
+	clock := glock.NewMockClock()
+
+	col := NewAsyncCollector(WithClock(clock), WithMaxConcurrency(1))
+
+	release := make(chan struct{})
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		<-release
+		return NewValueResult(1, nil)
+	})
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		return NewValueResult(2, nil)
+	})
+
+	go clock.BlockingAdvance(10 * time.Millisecond)
+	res, err := col.Wait(10 * time.Millisecond)
+	Expect(res).To(BeNil())
+	Expect(err).To(Equal(ErrTimeout))
+
+	close(release)
+
+	res, err = col.Wait(0)
+	Expect(err).To(BeNil())
+	Expect(res).To(Equal([]TaskResult{
+		NewValueResult(1, nil),
+		NewErrorResult(ErrCancelled),
+	}))
+}
+
+func (s *AsyncColSuite) TestFailFastFirstError(t sweet.T) {
+	col := NewAsyncCollector(WithFailFast(FailOnFirstError()))
+
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		<-task.Stopping()
+		return NewValueResult(1, nil)
+	})
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		return NewErrorResult(errors.New("I'm an error! - Ralph"))
+	})
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		<-task.Stopping()
+		return NewValueResult(3, nil)
+	})
+
+	closed := make(chan TaskResult, 3)
+	res, err := col.WaitCloser(time.Second, func(res TaskResult) {
+		closed <- res
+	})
+	Expect(res).To(BeNil())
+	Expect(err).To(Equal(errors.New("I'm an error! - Ralph")))
+
+	var closedResults []TaskResult
+	for i := 0; i < 3; i++ {
+		var res TaskResult
+		Eventually(closed).Should(Receive(&res))
+		closedResults = append(closedResults, res)
+	}
+	Expect(closedResults).To(ConsistOf(
+		NewValueResult(1, nil),
+		NewErrorResult(errors.New("I'm an error! - Ralph")),
+		NewValueResult(3, nil),
+	))
+
+	res, err = col.Wait(time.Second)
+	Expect(res).To(BeNil())
+	Expect(err).To(Equal(errors.New("I'm an error! - Ralph")))
+}
+
+func (s *AsyncColSuite) TestFailFastErrorCount(t sweet.T) {
+	col := NewAsyncCollector(WithFailFast(FailOnErrorCount(2)))
+
+	release := make(chan struct{})
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		return NewErrorResult(errors.New("first"))
+	})
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		<-release
+		return NewErrorResult(errors.New("second"))
+	})
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		<-task.Stopping()
+		return NewValueResult(3, nil)
+	})
+
+	resChan := col.Stream(0)
+	Eventually(resChan).Should(Receive(Equal(IndexedResult{
+		Index:  0,
+		Result: NewErrorResult(errors.New("first")),
+	})))
+	close(release)
+	Eventually(resChan).Should(Receive(Equal(IndexedResult{
+		Index:  1,
+		Result: NewErrorResult(errors.New("second")),
+	})))
+	Eventually(resChan).Should(BeClosed())
+
+	res, err := col.Wait(time.Second)
+	Expect(res).To(BeNil())
+	Expect(err).To(Equal(errors.New("second")))
+	Eventually(col.tasks[2].task.Finished()).Should(BeClosed())
+}
+
+func (s *AsyncColSuite) TestFailFastErrorMatch(t sweet.T) {
+	errFatal := errors.New("fatal")
+	col := NewAsyncCollector(WithFailFast(FailOnErrorMatch(func(err error) bool {
+		return err == errFatal
+	})))
+
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		return NewErrorResult(errors.New("not fatal"))
+	})
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		return NewValueResult(2, nil)
+	})
+
+	res, err := col.Wait(time.Second)
+	Expect(err).To(BeNil())
+	Expect(res).To(Equal([]TaskResult{
+		NewErrorResult(errors.New("not fatal")),
+		NewValueResult(2, nil),
+	}))
+}
+
+func (s *AsyncColSuite) TestWaitPartial(t sweet.T) {
+	clock := glock.NewMockClock()
+
+	col := NewAsyncCollector(WithClock(clock), WithMaxConcurrency(1))
+
+	var running sync.WaitGroup
+	running.Add(2)
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		running.Done()
+		return NewValueResult(1, nil)
+	})
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		running.Done()
+		<-task.Stopping()
+		return NewValueResult(2, nil)
+	})
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		<-task.Stopping()
+		return NewValueResult(3, nil)
+	})
+
+	running.Wait()
+
+	go clock.BlockingAdvance(10 * time.Millisecond)
+
+	res, statuses, err := col.WaitPartial(10 * time.Millisecond)
+	Expect(res).To(Equal([]TaskResult{NewValueResult(1, nil), nil, nil}))
+	Expect(statuses).To(Equal([]ResultStatus{ResultCompleted, ResultRunning, ResultNotStarted}))
+	Expect(err).To(Equal(&TimeoutError{Outstanding: []int{1, 2}}))
+	Expect(IsTimeout(err)).To(BeTrue())
+
+	col.tasks[1].task.Stop()
+	col.tasks[2].task.cancelCtx()
+
+	res, err = col.Wait(0)
+	Expect(err).To(BeNil())
+	Expect(res).To(Equal([]TaskResult{
+		NewValueResult(1, nil),
+		NewValueResult(2, nil),
+		NewValueResult(3, nil),
+	}))
+}
+
+func (s *AsyncColSuite) TestWaitPartialComplete(t sweet.T) {
+	col := NewAsyncCollector()
+
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		return NewValueResult(1, nil)
+	})
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		return NewValueResult(2, nil)
+	})
+
+	res, statuses, err := col.WaitPartial(0)
+	Expect(err).To(BeNil())
+	Expect(res).To(Equal([]TaskResult{NewValueResult(1, nil), NewValueResult(2, nil)}))
+	Expect(statuses).To(Equal([]ResultStatus{ResultCompleted, ResultCompleted}))
+}
+
+func (s *AsyncColSuite) TestStream(t sweet.T) {
+	col := NewAsyncCollector()
+
+	release := make(chan struct{})
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		<-release
+		return NewValueResult(1, nil)
+	})
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		return NewValueResult(2, nil)
+	})
+
+	resChan := col.Stream(time.Second)
+
+	Eventually(resChan).Should(Receive(Equal(IndexedResult{Index: 1, Result: NewValueResult(2, nil)})))
+	Consistently(resChan).ShouldNot(Receive())
+
+	close(release)
+
+	Eventually(resChan).Should(Receive(Equal(IndexedResult{Index: 0, Result: NewValueResult(1, nil)})))
+	Eventually(resChan).Should(BeClosed())
+
+	res, err := col.Wait(0)
+	Expect(err).To(BeNil())
+	Expect(res).To(Equal([]TaskResult{NewValueResult(1, nil), NewValueResult(2, nil)}))
+}
+
+func (s *AsyncColSuite) TestStreamTimeout(t sweet.T) {
+	clock := glock.NewMockClock()
+
+	col := NewAsyncCollector(WithClock(clock))
+
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		return NewValueResult(1, nil)
+	})
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		<-task.Stopping()
+		return NewValueResult(2, nil)
+	})
+
+	resChan := col.Stream(10 * time.Millisecond)
+	Eventually(resChan).Should(Receive(Equal(IndexedResult{Index: 0, Result: NewValueResult(1, nil)})))
+
+	go clock.BlockingAdvance(10 * time.Millisecond)
+	Eventually(resChan).Should(BeClosed())
+
+	col.tasks[1].task.Stop()
+
+	res, err := col.Wait(0)
+	Expect(err).To(BeNil())
+	Expect(res).To(Equal([]TaskResult{NewValueResult(1, nil), NewValueResult(2, nil)}))
+}
+
+func (s *AsyncColSuite) TestStreamContext(t sweet.T) {
+	col := NewAsyncCollector()
+
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		<-task.Stopping()
+		return NewValueResult(1, nil)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	resChan := col.StreamWithContext(ctx, 0)
+	Consistently(resChan).ShouldNot(BeClosed())
+
+	cancel()
+	Eventually(resChan).Should(BeClosed())
+
+	col.tasks[0].task.Stop()
+	res, err := col.Wait(0)
+	Expect(err).To(BeNil())
+	Expect(res).To(Equal([]TaskResult{NewValueResult(1, nil)}))
+}
+
+func (s *AsyncColSuite) TestRunWithConfigTimeout(t sweet.T) {
+	clock := glock.NewMockClock()
+
+	col := NewAsyncCollector(WithClock(clock))
+
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		return NewValueResult(1, nil)
+	})
+	col.RunWithConfig(context.Background(), []TaskConfig{WithTaskTimeout(time.Second)}, func(task *Task, data ...interface{}) TaskResult {
+		<-task.Stopping()
+		return NewValueResult(2, nil)
+	})
+
+	go clock.BlockingAdvance(time.Second)
+
+	res, err := col.Wait(0)
+	Expect(err).To(BeNil())
+	Expect(res).To(Equal([]TaskResult{
+		NewValueResult(1, nil),
+		NewErrorResult(ErrDeadlineExceeded),
+	}))
+}
+
+func (s *AsyncColSuite) TestTasks(t sweet.T) {
+	c := NewAsyncCollector(WithMaxConcurrency(1))
+	c.Run(func(task *Task, args ...interface{}) TaskResult {
+		<-task.Stopping()
+		return nil
+	})
+	c.Run(func(task *Task, args ...interface{}) TaskResult {
+		return nil
+	})
+
+	tasks := c.Tasks()
+	Expect(tasks).To(HaveLen(2))
+	Expect(tasks[0].ID() < tasks[1].ID()).To(BeTrue())
+
+	go func() {
+		<-tasks[0].Started()
+		tasks[0].Stop()
+	}()
+
+	_, err := c.Wait(0)
+	Expect(err).To(BeNil())
+	after := c.Tasks()
+	Expect(after).To(HaveLen(2))
+	Expect(after[0]).To(BeIdenticalTo(tasks[0]))
+	Expect(after[1]).To(BeIdenticalTo(tasks[1]))
+}
+
+func (s *AsyncColSuite) TestIsTimeout(t sweet.T) {
+	Expect(IsTimeout(ErrTimeout)).To(BeTrue())
+	Expect(IsTimeout(&TimeoutError{Outstanding: []int{0}})).To(BeTrue())
+	Expect(IsTimeout(ErrCancelled)).To(BeFalse())
+	Expect(IsTimeout(nil)).To(BeFalse())
+}
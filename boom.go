@@ -2,13 +2,19 @@ package boom
 
 import (
 	"context"
+	"fmt"
 	"reflect"
+	"runtime/debug"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 var nilValue = reflect.ValueOf(nil)
 
+// lastTaskID is the ID of the most recently created task
+var lastTaskID uint64
+
 // TaskFunc is the signature for the function executed to perform a Task
 type TaskFunc func(task *Task, data ...interface{}) TaskResult
 
@@ -19,17 +25,23 @@ type TaskResult interface {
 
 // Task represents a task being executed within boom
 type Task struct {
+	// goroutine is the ID of the goroutine executing the task's function. It's
+	// accessed atomically so it's kept first to be 64-bit aligned.
+	goroutine uint64
+
 	cfg *taskConfig
 
-	ctx       context.Context
+	ctx       *taskContext
 	cancelCtx context.CancelFunc
 
 	f    TaskFunc
 	args []interface{}
 
-	startedChan  chan struct{}
-	runningChan  chan struct{}
-	finishedChan chan struct{}
+	startLock     sync.Mutex
+	scheduledChan chan struct{}
+	startedChan   chan struct{}
+	runningChan   chan struct{}
+	finishedChan  chan struct{}
 
 	resultLock sync.RWMutex
 	resultChan chan TaskResult
@@ -37,6 +49,19 @@ type Task struct {
 
 	discardOnce  sync.Once
 	completeOnce sync.Once
+	stopOnce     sync.Once
+
+	attempts int32
+
+	// onFinish is called when the task finishes, if set
+	onFinish func(*Task)
+
+	id         uint64
+	name       string
+	labels     map[string]string
+	stateLock  sync.RWMutex
+	stateTimes [numTaskStates]time.Time
+	running    int32
 }
 
 // newTask creates a new task with the given function and arguments
@@ -46,19 +71,38 @@ func newTask(ctx context.Context, cfg *taskConfig, f TaskFunc, args ...interface
 	task := &Task{
 		cfg: cfg,
 
-		ctx:       ctx,
-		cancelCtx: cancelCtx,
+		ctx: newTaskContext(ctx),
 
 		f:    f,
 		args: args,
 
-		startedChan:  make(chan struct{}),
-		runningChan:  make(chan struct{}),
-		finishedChan: make(chan struct{}),
+		scheduledChan: make(chan struct{}),
+		startedChan:   make(chan struct{}),
+		runningChan:   make(chan struct{}),
+		finishedChan:  make(chan struct{}),
 
 		resultChan: make(chan TaskResult),
+
+		id:     atomic.AddUint64(&lastTaskID, 1),
+		name:   cfg.name,
+		labels: make(map[string]string, len(cfg.labels)),
+	}
+
+	task.cancelCtx = func() {
+		task.stopOnce.Do(func() {
+			task.setState(TaskStopping)
+			cfg.eachHook(func(h Hooks) { h.OnStop(task) })
+		})
+		cancelCtx()
+	}
+
+	for key, value := range cfg.labels {
+		task.labels[key] = value
 	}
 
+	task.setState(TaskCreated)
+	cfg.eachHook(func(h Hooks) { h.OnCreate(task) })
+
 	return task
 }
 
@@ -99,6 +143,9 @@ func (t *Task) Finished() <-chan struct{} {
 
 // Start will begin execution of the task in a separate goroutine.
 func (t *Task) Start() error {
+	t.startLock.Lock()
+	defer t.startLock.Unlock()
+
 	// If the task has already finished, return an error
 	select {
 	case <-t.finishedChan:
@@ -115,11 +162,22 @@ func (t *Task) Start() error {
 	}
 
 	close(t.startedChan)
+	t.setState(TaskStarted)
+	t.cfg.eachHook(func(h Hooks) { h.OnStart(t) })
+	span := t.startSpan()
 
 	go func(task *Task) {
-		res := task.f(t, task.args...)
+		res := task.run()
+		task.endSpan(span, res)
+
+		t.setState(TaskFinished)
+		duration := t.StateTime(TaskFinished).Sub(t.StateTime(TaskStarted))
+		t.cfg.eachHook(func(h Hooks) { h.OnFinish(t, res, duration) })
 
 		close(t.finishedChan)
+		if t.onFinish != nil {
+			t.onFinish(t)
+		}
 
 		task.resultChan <- res
 	}(t)
@@ -127,14 +185,144 @@ func (t *Task) Start() error {
 	return nil
 }
 
+// schedule marks the task as scheduled to be started later. Once scheduled, a
+// task can be waited on or stopped before it has started. If the task is stopped
+// before it starts, whatever scheduled it is expected to call skip rather than
+// starting the task.
+func (t *Task) schedule() {
+	t.startLock.Lock()
+	defer t.startLock.Unlock()
+
+	select {
+	case <-t.scheduledChan:
+	default:
+		close(t.scheduledChan)
+	}
+}
+
+// skip finishes a task that hasn't started with the given result, without
+// executing the task's function. It returns false if the task has already
+// started or finished.
+func (t *Task) skip(res TaskResult) bool {
+	t.startLock.Lock()
+	defer t.startLock.Unlock()
+
+	select {
+	case <-t.startedChan:
+		return false
+	case <-t.finishedChan:
+		return false
+	default:
+	}
+
+	t.setState(TaskFinished)
+	t.cfg.eachHook(func(h Hooks) { h.OnFinish(t, res, 0) })
+
+	close(t.finishedChan)
+	if t.onFinish != nil {
+		t.onFinish(t)
+	}
+
+	go func() {
+		t.resultChan <- res
+	}()
+
+	return true
+}
+
+// executing returns true if the task has been started or scheduled to start.
+func (t *Task) executing() bool {
+	select {
+	case <-t.startedChan:
+		return true
+	case <-t.scheduledChan:
+		return true
+	default:
+		return false
+	}
+}
+
+// run executes the task, enforcing the task's timeout if one is configured.
+// If the timeout elapses before the task function returns, the task is cancelled
+// and an ErrDeadlineExceeded result is returned once the function returns.
+func (t *Task) run() TaskResult {
+	timeout := t.cfg.taskTimeout
+	if timeout <= 0 {
+		return t.execute()
+	}
+
+	t.ctx.setDeadline(t.cfg.clock.Now().Add(timeout))
+	deadlineChan := t.cfg.clock.After(timeout)
+
+	resChan := make(chan TaskResult, 1)
+	go func() {
+		resChan <- t.execute()
+	}()
+
+	select {
+	case res := <-resChan:
+		return res
+	case <-deadlineChan:
+		t.ctx.setExceeded()
+		t.cancelCtx()
+		t.cfg.eachHook(func(h Hooks) { h.OnTimeout(t) })
+
+		// Wait for the function so it isn't still running after the task is
+		// reported as finished, e.g. after a runner shuts down or a supervisor
+		// restarts it.
+		<-resChan
+		return NewErrorResult(ErrDeadlineExceeded)
+	}
+}
+
+// execute calls the task function, recovering from any panic that occurs
+// and converting it into a PanicResult.
+func (t *Task) execute() (res TaskResult) {
+	t.trackGoroutine()
+	defer func() {
+		t.setGoroutine(0)
+
+		if r := recover(); r != nil {
+			panicRes := NewPanicResult(r, debug.Stack())
+			if t.cfg.panicHandler != nil {
+				t.cfg.panicHandler(t, panicRes)
+			}
+			res = panicRes
+		}
+	}()
+
+	if t.cfg.retry != nil {
+		return t.cfg.retry.run(t, t.f, t.args...)
+	}
+
+	t.setAttempts(1)
+	return t.f(t, t.args...)
+}
+
+// Attempts returns the number of times the task's function has been called. This
+// is greater than 1 if the function has been retried.
+func (t *Task) Attempts() int {
+	return int(atomic.LoadInt32(&t.attempts))
+}
+
+func (t *Task) setAttempts(attempts int) {
+	atomic.StoreInt32(&t.attempts, int32(attempts))
+}
+
 // SetRunning is a utility provided to users to signal whether a task is
 // actively running or not.  Typically this would be used within the task
 // function itself to signal that it has completed any setup it needed to
 // do and has started processing data.
 func (t *Task) SetRunning(running bool) {
 	if !running {
+		atomic.StoreInt32(&t.running, 0)
 		t.runningChan = make(chan struct{})
 	} else {
+		if atomic.SwapInt32(&t.running, 1) == 0 {
+			t.setState(TaskRunning)
+			t.cfg.eachHook(func(h Hooks) { h.OnRunning(t) })
+		}
+
 		select {
 		case <-t.runningChan:
 		default:
@@ -157,16 +345,16 @@ func (t *Task) WaitForRunning(timeout time.Duration) error {
 	case <-t.runningChan:
 		return nil
 	case <-timeoutChan:
+		t.cfg.eachHook(func(h Hooks) { h.OnTimeout(t) })
 		return ErrTimeout
 	}
 }
 
 // Stop signals a started task to stop. It is up to the task
-// itself to check Task.Stopping() to see if it should stop.
+// itself to check Task.Stopping() to see if it should stop. A task
+// that is scheduled to start later will not be started.
 func (t *Task) Stop() error {
-	select {
-	case <-t.startedChan:
-	default:
+	if !t.executing() {
 		return ErrNotExecuting
 	}
 
@@ -181,6 +369,8 @@ func (t *Task) Discard() {
 	// Start a goroutine to listen to the task result channel and discard the result,
 	// then exit.
 	t.discardOnce.Do(func() {
+		t.cfg.eachHook(func(h Hooks) { h.OnDiscard(t) })
+
 		go func() {
 			<-t.resultChan
 			t.completed(nil)
@@ -193,9 +383,7 @@ func (t *Task) Discard() {
 // Wait will wait until the timeout duration and close the channel. Either Discard or Wait must
 // be called or the task's goroutine will leak.
 func (t *Task) Wait(timeout time.Duration) (TaskResult, error) {
-	select {
-	case <-t.startedChan:
-	default:
+	if !t.executing() {
 		return nil, ErrNotExecuting
 	}
 
@@ -217,6 +405,7 @@ func (t *Task) Wait(timeout time.Duration) (TaskResult, error) {
 		t.SetRunning(false)
 		return res, nil
 	case <-timeoutChan:
+		t.cfg.eachHook(func(h Hooks) { h.OnTimeout(t) })
 		return nil, ErrTimeout
 	}
 }
@@ -282,3 +471,28 @@ func NewErrorResult(err error) *ErrorResult {
 func (r *ErrorResult) Err() error {
 	return r.Error
 }
+
+// PanicResult is the result of a task whose function panicked while executing.
+type PanicResult struct {
+	// Value is the value recovered from the panic
+	Value interface{}
+	// Stack is the stack trace of the goroutine at the time of the panic
+	Stack []byte
+}
+
+// NewPanicResult is a convenience function for creating a PanicResult
+func NewPanicResult(value interface{}, stack []byte) *PanicResult {
+	return &PanicResult{
+		Value: value,
+		Stack: stack,
+	}
+}
+
+// Err always returns ErrPanic
+func (r *PanicResult) Err() error {
+	return ErrPanic
+}
+
+func (r *PanicResult) String() string {
+	return fmt.Sprintf("panic: %v\n\n%s", r.Value, r.Stack)
+}
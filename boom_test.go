@@ -9,9 +9,13 @@ import (
 
 	"github.com/aphistic/sweet"
 	junit "github.com/aphistic/sweet-junit"
+	"github.com/efritz/glock"
 	. "github.com/onsi/gomega"
 )
 
+// versionedSuites holds suites for code that is only built by some versions of Go
+var versionedSuites []interface{}
+
 func TestMain(m *testing.M) {
 	RegisterFailHandler(sweet.GomegaFail)
 
@@ -21,6 +25,24 @@ func TestMain(m *testing.M) {
 		s.AddSuite(&TaskSuite{})
 		s.AddSuite(&RunnerSuite{})
 		s.AddSuite(&AsyncColSuite{})
+		s.AddSuite(&RaceColSuite{})
+		s.AddSuite(&QuorumColSuite{})
+		s.AddSuite(&RetrySuite{})
+		s.AddSuite(&DAGSuite{})
+		s.AddSuite(&PoolRunnerSuite{})
+		s.AddSuite(&HooksSuite{})
+		s.AddSuite(&TracingSuite{})
+		s.AddSuite(&SchedulerSuite{})
+		s.AddSuite(&CronSuite{})
+		s.AddSuite(&SupervisorSuite{})
+		s.AddSuite(&DedupSuite{})
+		s.AddSuite(&CacheSuite{})
+		s.AddSuite(&RateLimitSuite{})
+		s.AddSuite(&BreakerSuite{})
+
+		for _, suite := range versionedSuites {
+			s.AddSuite(suite)
+		}
 	})
 }
 
@@ -410,3 +432,150 @@ func (s *TaskSuite) TestErrorResultErr(t sweet.T) {
 	res := NewErrorResult(errors.New("I'm an error - Ralph"))
 	Expect(res.Err()).To(Equal(errors.New("I'm an error - Ralph")))
 }
+
+func (s *TaskSuite) TestPanicRecovered(t sweet.T) {
+	task := runTask(context.Background(), newTaskConfig(), func(task *Task, args ...interface{}) TaskResult {
+		panic("I'm a panic! - Ralph")
+	})
+
+	res, err := task.Wait(waitTimeout)
+	Expect(err).To(BeNil())
+	Expect(res.Err()).To(Equal(ErrPanic))
+
+	panicRes, ok := res.(*PanicResult)
+	Expect(ok).To(BeTrue())
+	Expect(panicRes.Value).To(Equal("I'm a panic! - Ralph"))
+	Expect(string(panicRes.Stack)).To(ContainSubstring("TestPanicRecovered"))
+	Expect(task.Finished()).To(BeClosed())
+}
+
+func (s *TaskSuite) TestPanicHandler(t sweet.T) {
+	var handled *PanicResult
+	var handledTask *Task
+
+	cfg := newTaskConfig()
+	cfg.ApplyConfigs([]TaskConfig{
+		WithPanicHandler(func(task *Task, res *PanicResult) {
+			handledTask = task
+			handled = res
+		}),
+	})
+
+	task := runTask(context.Background(), cfg, func(task *Task, args ...interface{}) TaskResult {
+		panic(errors.New("I'm an error! - Ralph"))
+	})
+
+	res, err := task.Wait(waitTimeout)
+	Expect(err).To(BeNil())
+	Expect(res).To(Equal(handled))
+	Expect(handledTask).To(Equal(task))
+	Expect(handled.Value).To(Equal(errors.New("I'm an error! - Ralph")))
+}
+
+func (s *TaskSuite) TestID(t sweet.T) {
+	first := newTask(context.Background(), newTaskConfig(), nil)
+	second := newTask(context.Background(), newTaskConfig(), nil)
+
+	Expect(first.ID()).ToNot(BeZero())
+	Expect(second.ID()).To(BeNumerically(">", first.ID()))
+}
+
+func (s *TaskSuite) TestState(t sweet.T) {
+	clock := glock.NewMockClockAt(time.Unix(100, 0))
+
+	cfg := newTaskConfig()
+	cfg.ApplyConfigs([]TaskConfig{WithClock(clock)})
+
+	advance := make(chan struct{})
+	advanced := make(chan struct{})
+	task := newTask(context.Background(), cfg, func(task *Task, args ...interface{}) TaskResult {
+		<-advance
+		task.SetRunning(true)
+		advanced <- struct{}{}
+		<-task.Stopping()
+		return nil
+	})
+	Expect(task.State()).To(Equal(TaskCreated))
+
+	clock.Advance(time.Second)
+	task.Start()
+	Expect(task.State()).To(Equal(TaskStarted))
+
+	clock.Advance(time.Second)
+	advance <- struct{}{}
+	<-advanced
+	Expect(task.State()).To(Equal(TaskRunning))
+
+	clock.Advance(time.Second)
+	task.Stop()
+	Expect(task.State()).To(Equal(TaskStopping))
+
+	clock.Advance(time.Second)
+	task.Wait(0)
+	Expect(task.State()).To(Equal(TaskFinished))
+
+	snapshot := task.Snapshot()
+	Expect(snapshot.ID).To(Equal(task.ID()))
+	Expect(snapshot.State).To(Equal(TaskFinished))
+	Expect(snapshot.Times[TaskCreated]).To(Equal(time.Unix(100, 0)))
+	Expect(snapshot.Times[TaskStarted]).To(Equal(time.Unix(101, 0)))
+	Expect(snapshot.Times[TaskRunning]).To(Equal(time.Unix(102, 0)))
+	Expect(snapshot.Times[TaskStopping]).To(Equal(time.Unix(103, 0)))
+	Expect(snapshot.Times[TaskFinished]).To(Equal(time.Unix(104, 0)))
+	Expect(task.StateTime(TaskStarted)).To(Equal(time.Unix(101, 0)))
+}
+
+func (s *TaskSuite) TestStateParentCancelled(t sweet.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	task := runTask(ctx, newTaskConfig(), func(task *Task, args ...interface{}) TaskResult {
+		<-task.Stopping()
+		return nil
+	})
+	defer task.Wait(0)
+
+	cancel()
+	Eventually(task.State).Should(Equal(TaskStopping))
+	Expect(task.StateTime(TaskStopping)).ToNot(BeZero())
+}
+
+func (s *TaskSuite) TestStateString(t sweet.T) {
+	Expect(TaskCreated.String()).To(Equal("created"))
+	Expect(TaskStopping.String()).To(Equal("stopping"))
+	Expect(TaskState(100).String()).To(Equal("unknown"))
+}
+
+func stackTask(task *Task, args ...interface{}) TaskResult {
+	task.SetRunning(true)
+	<-task.Stopping()
+	return nil
+}
+
+func (s *TaskSuite) TestStack(t sweet.T) {
+	EnableTaskStacks()
+
+	task := newTask(context.Background(), newTaskConfig(), stackTask)
+	Expect(task.Stack()).To(BeNil())
+
+	task.Start()
+	Expect(task.WaitForRunning(0)).To(BeNil())
+	Expect(string(task.Stack())).To(HavePrefix("goroutine "))
+	Expect(string(task.Stack())).To(ContainSubstring("stackTask"))
+
+	task.StopAndWait(0)
+	Expect(task.Stack()).To(BeNil())
+}
+
+func (s *TaskSuite) TestTaskStacks(t sweet.T) {
+	EnableTaskStacks()
+
+	running := newTask(context.Background(), newTaskConfig(), stackTask)
+	running.Start()
+	Expect(running.WaitForRunning(0)).To(BeNil())
+	defer running.StopAndWait(0)
+
+	unstarted := newTask(context.Background(), newTaskConfig(), stackTask)
+
+	stacks := TaskStacks([]*Task{running, unstarted})
+	Expect(stacks).To(HaveLen(1))
+	Expect(string(stacks[running.ID()])).To(ContainSubstring("stackTask"))
+}
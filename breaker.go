@@ -0,0 +1,274 @@
+package boom
+
+import (
+	"sync"
+	"time"
+)
+
+// CircuitState is the state of a CircuitBreaker
+type CircuitState int
+
+const (
+	// CircuitClosed means calls are allowed and their outcomes are tracked
+	CircuitClosed CircuitState = iota
+	// CircuitOpen means calls fail right away with ErrCircuitOpen
+	CircuitOpen
+	// CircuitHalfOpen means a limited number of trial calls are allowed to
+	// decide whether the circuit should close again
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// BreakerPolicy determines when a CircuitBreaker opens and closes.
+type BreakerPolicy struct {
+	// Window is the number of most recent outcomes tracked while the circuit is
+	// closed. If Window is 0, 10 outcomes are tracked.
+	Window int
+	// MinCalls is the number of outcomes needed in the window before the circuit
+	// can open. If MinCalls is 0, the window must be full.
+	MinCalls int
+	// FailureRatio is the fraction of failed outcomes in the window, between 0
+	// and 1, that opens the circuit. If FailureRatio is 0, 0.5 is used.
+	FailureRatio float64
+	// OpenTimeout is how long the circuit stays open before allowing trial calls.
+	OpenTimeout time.Duration
+	// HalfOpenCalls is the number of trial calls allowed while the circuit is
+	// half-open. The circuit closes once they all succeed and opens again if any
+	// of them fail. If HalfOpenCalls is 0, 1 is used.
+	HalfOpenCalls int
+	// IsFailure returns whether a call that returned err failed. If IsFailure is
+	// nil every error is a failure.
+	IsFailure func(err error) bool
+	// OnStateChange is called when the circuit changes state, if it's set. It's
+	// called from the goroutine of the task that caused the change.
+	OnStateChange func(from CircuitState, to CircuitState)
+}
+
+// CircuitBreaker wraps TaskFuncs so they fail fast while the functions they call
+// are failing. Outcomes of calls are tracked over a sliding window, and once too
+// many have failed the circuit opens and calls return an ErrorResult with
+// ErrCircuitOpen without calling the function. After OpenTimeout, measured using
+// the clock of the task making the call, the circuit is half-open and allows trial
+// calls to decide whether it should close again. A panic is counted as a failure.
+type CircuitBreaker struct {
+	policy BreakerPolicy
+
+	lock  sync.Mutex
+	state CircuitState
+	// generation is incremented on each state change so outcomes of calls that
+	// were allowed before the change are ignored
+	generation uint64
+	openedAt   time.Time
+
+	// outcomes is a ring buffer of the outcomes tracked while closed, with true
+	// meaning the call failed
+	outcomes []bool
+	next     int
+	count    int
+	failures int
+
+	trials    int
+	successes int
+}
+
+// NewCircuitBreaker creates a new CircuitBreaker using the given policy.
+func NewCircuitBreaker(policy BreakerPolicy) *CircuitBreaker {
+	if policy.Window <= 0 {
+		policy.Window = 10
+	}
+	if policy.MinCalls <= 0 || policy.MinCalls > policy.Window {
+		policy.MinCalls = policy.Window
+	}
+	if policy.FailureRatio <= 0 {
+		policy.FailureRatio = 0.5
+	}
+	if policy.HalfOpenCalls <= 0 {
+		policy.HalfOpenCalls = 1
+	}
+
+	return &CircuitBreaker{
+		policy:   policy,
+		outcomes: make([]bool, policy.Window),
+	}
+}
+
+// Wrap wraps f so that it's called through the circuit breaker. Every function
+// wrapped by the same CircuitBreaker shares its state.
+func (cb *CircuitBreaker) Wrap(f TaskFunc) TaskFunc {
+	return func(task *Task, args ...interface{}) TaskResult {
+		generation, ok := cb.allow(task.cfg.clock.Now())
+		if !ok {
+			return NewErrorResult(ErrCircuitOpen)
+		}
+
+		failed := true
+		defer func() {
+			cb.record(task.cfg.clock.Now(), generation, failed)
+		}()
+
+		res := f(task, args...)
+		failed = cb.isFailure(res)
+
+		return res
+	}
+}
+
+// State returns the current state of the circuit. An open circuit reports
+// CircuitOpen until a call is made after OpenTimeout has passed.
+func (cb *CircuitBreaker) State() CircuitState {
+	cb.lock.Lock()
+	defer cb.lock.Unlock()
+
+	return cb.state
+}
+
+// Reset closes the circuit and clears its tracked outcomes.
+func (cb *CircuitBreaker) Reset() {
+	cb.lock.Lock()
+	from, changed := cb.setState(CircuitClosed, time.Time{})
+	cb.lock.Unlock()
+
+	if changed {
+		cb.stateChanged(from, CircuitClosed)
+	}
+}
+
+func (cb *CircuitBreaker) isFailure(res TaskResult) bool {
+	if res == nil || res.Err() == nil {
+		return false
+	}
+	if cb.policy.IsFailure != nil {
+		return cb.policy.IsFailure(res.Err())
+	}
+	return true
+}
+
+// allow returns whether a call may be made and the generation it was allowed in
+func (cb *CircuitBreaker) allow(now time.Time) (uint64, bool) {
+	cb.lock.Lock()
+
+	var changed bool
+	if cb.state == CircuitOpen && !now.Before(cb.openedAt.Add(cb.policy.OpenTimeout)) {
+		_, changed = cb.setState(CircuitHalfOpen, now)
+	}
+
+	allowed := true
+	switch cb.state {
+	case CircuitOpen:
+		allowed = false
+	case CircuitHalfOpen:
+		if cb.trials >= cb.policy.HalfOpenCalls {
+			allowed = false
+		} else {
+			cb.trials++
+		}
+	}
+	generation := cb.generation
+
+	cb.lock.Unlock()
+
+	if changed {
+		cb.stateChanged(CircuitOpen, CircuitHalfOpen)
+	}
+
+	return generation, allowed
+}
+
+// record records the outcome of a call allowed in the given generation
+func (cb *CircuitBreaker) record(now time.Time, generation uint64, failed bool) {
+	cb.lock.Lock()
+
+	if generation != cb.generation {
+		cb.lock.Unlock()
+		return
+	}
+
+	var (
+		from    CircuitState
+		to      CircuitState
+		changed bool
+	)
+
+	switch cb.state {
+	case CircuitClosed:
+		if cb.count == len(cb.outcomes) && cb.outcomes[cb.next] {
+			cb.failures--
+		}
+		cb.outcomes[cb.next] = failed
+		cb.next = (cb.next + 1) % len(cb.outcomes)
+		if cb.count < len(cb.outcomes) {
+			cb.count++
+		}
+		if failed {
+			cb.failures++
+		}
+
+		if cb.count >= cb.policy.MinCalls &&
+			float64(cb.failures)/float64(cb.count) >= cb.policy.FailureRatio {
+			to = CircuitOpen
+			from, changed = cb.setState(to, now)
+		}
+	case CircuitHalfOpen:
+		if failed {
+			to = CircuitOpen
+			from, changed = cb.setState(to, now)
+			break
+		}
+
+		cb.successes++
+		if cb.successes >= cb.policy.HalfOpenCalls {
+			to = CircuitClosed
+			from, changed = cb.setState(to, now)
+		}
+	}
+
+	cb.lock.Unlock()
+
+	if changed {
+		cb.stateChanged(from, to)
+	}
+}
+
+// setState moves the circuit to the given state, resetting what's tracked for
+// the previous state, and returns the previous state and whether it changed.
+// The lock must be held.
+func (cb *CircuitBreaker) setState(state CircuitState, now time.Time) (CircuitState, bool) {
+	from := cb.state
+
+	cb.state = state
+	cb.generation++
+	cb.trials = 0
+	cb.successes = 0
+
+	switch state {
+	case CircuitOpen:
+		cb.openedAt = now
+	case CircuitClosed:
+		for idx := range cb.outcomes {
+			cb.outcomes[idx] = false
+		}
+		cb.next = 0
+		cb.count = 0
+		cb.failures = 0
+	}
+
+	return from, from != state
+}
+
+func (cb *CircuitBreaker) stateChanged(from CircuitState, to CircuitState) {
+	if cb.policy.OnStateChange != nil {
+		cb.policy.OnStateChange(from, to)
+	}
+}
@@ -0,0 +1,233 @@
+package boom
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aphistic/sweet"
+	"github.com/efritz/glock"
+	. "github.com/onsi/gomega"
+)
+
+type BreakerSuite struct{}
+
+// breakerCall runs f through the breaker and returns the result
+func breakerCall(tr *TaskRunner, f TaskFunc, fail bool) TaskResult {
+	res, err := tr.Run(f, fail).Wait(0)
+	Expect(err).To(BeNil())
+	return res
+}
+
+// failingFunc returns an error if its first argument is true
+func failingFunc(task *Task, args ...interface{}) TaskResult {
+	if args[0].(bool) {
+		return NewErrorResult(errors.New("failed"))
+	}
+	return NewValueResult("ok", nil)
+}
+
+type stateChanges struct {
+	lock    sync.Mutex
+	changes []string
+}
+
+func (s *stateChanges) record(from CircuitState, to CircuitState) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.changes = append(s.changes, fmt.Sprintf("%s -> %s", from, to))
+}
+
+func (s *stateChanges) Changes() []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]string{}, s.changes...)
+}
+
+func (s *BreakerSuite) TestOpens(t sweet.T) {
+	changes := &stateChanges{}
+	cb := NewCircuitBreaker(BreakerPolicy{
+		Window:        4,
+		FailureRatio:  0.5,
+		OpenTimeout:   time.Minute,
+		OnStateChange: changes.record,
+	})
+	tr := NewTaskRunner(WithClock(glock.NewMockClock()))
+	f := cb.Wrap(failingFunc)
+
+	Expect(breakerCall(tr, f, false).Err()).To(BeNil())
+	Expect(breakerCall(tr, f, true).Err()).To(MatchError("failed"))
+	Expect(breakerCall(tr, f, false).Err()).To(BeNil())
+	Expect(cb.State()).To(Equal(CircuitClosed))
+
+	// The window isn't full until the fourth call
+	Expect(breakerCall(tr, f, true).Err()).To(MatchError("failed"))
+	Expect(cb.State()).To(Equal(CircuitOpen))
+	Expect(changes.Changes()).To(Equal([]string{"closed -> open"}))
+
+	called := false
+	res := breakerCall(tr, cb.Wrap(func(task *Task, args ...interface{}) TaskResult {
+		called = true
+		return nil
+	}), false)
+	Expect(res.Err()).To(Equal(ErrCircuitOpen))
+	Expect(called).To(BeFalse())
+}
+
+func (s *BreakerSuite) TestSlidingWindow(t sweet.T) {
+	cb := NewCircuitBreaker(BreakerPolicy{Window: 4, FailureRatio: 0.75})
+	tr := NewTaskRunner(WithClock(glock.NewMockClock()))
+	f := cb.Wrap(failingFunc)
+
+	for _, fail := range []bool{true, true, false, false, true, false, true} {
+		breakerCall(tr, f, fail)
+		Expect(cb.State()).To(Equal(CircuitClosed))
+	}
+
+	// Four of the seven calls failed, but the window is now only tracking
+	// [false, true, false, true], so one more failure pushes out a success
+	// and opens the circuit
+	breakerCall(tr, f, true)
+	Expect(cb.State()).To(Equal(CircuitOpen))
+}
+
+func (s *BreakerSuite) TestMinCalls(t sweet.T) {
+	cb := NewCircuitBreaker(BreakerPolicy{Window: 10, MinCalls: 2})
+	tr := NewTaskRunner(WithClock(glock.NewMockClock()))
+	f := cb.Wrap(failingFunc)
+
+	breakerCall(tr, f, true)
+	Expect(cb.State()).To(Equal(CircuitClosed))
+	breakerCall(tr, f, true)
+	Expect(cb.State()).To(Equal(CircuitOpen))
+}
+
+func (s *BreakerSuite) TestHalfOpen(t sweet.T) {
+	clock := glock.NewMockClock()
+	changes := &stateChanges{}
+	cb := NewCircuitBreaker(BreakerPolicy{
+		Window:        1,
+		OpenTimeout:   time.Minute,
+		HalfOpenCalls: 2,
+		OnStateChange: changes.record,
+	})
+	tr := NewTaskRunner(WithClock(clock))
+	f := cb.Wrap(failingFunc)
+
+	breakerCall(tr, f, true)
+	Expect(cb.State()).To(Equal(CircuitOpen))
+
+	clock.Advance(59 * time.Second)
+	Expect(breakerCall(tr, f, false).Err()).To(Equal(ErrCircuitOpen))
+
+	clock.Advance(time.Second)
+	Expect(breakerCall(tr, f, false).Err()).To(BeNil())
+	Expect(cb.State()).To(Equal(CircuitHalfOpen))
+	Expect(breakerCall(tr, f, false).Err()).To(BeNil())
+	Expect(cb.State()).To(Equal(CircuitClosed))
+
+	Expect(changes.Changes()).To(Equal([]string{
+		"closed -> open",
+		"open -> half-open",
+		"half-open -> closed",
+	}))
+}
+
+func (s *BreakerSuite) TestHalfOpenFails(t sweet.T) {
+	clock := glock.NewMockClock()
+	changes := &stateChanges{}
+	cb := NewCircuitBreaker(BreakerPolicy{
+		Window:        1,
+		OpenTimeout:   time.Minute,
+		OnStateChange: changes.record,
+	})
+	tr := NewTaskRunner(WithClock(clock))
+	f := cb.Wrap(failingFunc)
+
+	breakerCall(tr, f, true)
+	clock.Advance(time.Minute)
+
+	Expect(breakerCall(tr, f, true).Err()).To(MatchError("failed"))
+	Expect(cb.State()).To(Equal(CircuitOpen))
+	Expect(breakerCall(tr, f, false).Err()).To(Equal(ErrCircuitOpen))
+
+	Expect(changes.Changes()).To(Equal([]string{
+		"closed -> open",
+		"open -> half-open",
+		"half-open -> open",
+	}))
+}
+
+func (s *BreakerSuite) TestHalfOpenLimitsTrials(t sweet.T) {
+	clock := glock.NewMockClock()
+	cb := NewCircuitBreaker(BreakerPolicy{Window: 1, OpenTimeout: time.Minute})
+	tr := NewTaskRunner(WithClock(clock))
+
+	breakerCall(tr, cb.Wrap(failingFunc), true)
+	clock.Advance(time.Minute)
+
+	release := make(chan struct{})
+	trial := tr.Run(cb.Wrap(func(task *Task, args ...interface{}) TaskResult {
+		<-release
+		return nil
+	}))
+	Eventually(cb.State).Should(Equal(CircuitHalfOpen))
+
+	Expect(breakerCall(tr, cb.Wrap(failingFunc), false).Err()).To(Equal(ErrCircuitOpen))
+
+	close(release)
+	trial.Wait(0)
+	Expect(cb.State()).To(Equal(CircuitClosed))
+}
+
+func (s *BreakerSuite) TestPanicIsFailure(t sweet.T) {
+	cb := NewCircuitBreaker(BreakerPolicy{Window: 1})
+	tr := NewTaskRunner(WithClock(glock.NewMockClock()))
+
+	res := breakerCall(tr, cb.Wrap(func(task *Task, args ...interface{}) TaskResult {
+		panic("oops")
+	}), false)
+	Expect(res.Err()).To(Equal(ErrPanic))
+	Expect(cb.State()).To(Equal(CircuitOpen))
+}
+
+func (s *BreakerSuite) TestIsFailure(t sweet.T) {
+	ignored := errors.New("ignored")
+	cb := NewCircuitBreaker(BreakerPolicy{
+		Window: 1,
+		IsFailure: func(err error) bool {
+			return err != ignored
+		},
+	})
+	tr := NewTaskRunner(WithClock(glock.NewMockClock()))
+
+	breakerCall(tr, cb.Wrap(func(task *Task, args ...interface{}) TaskResult {
+		return NewErrorResult(ignored)
+	}), false)
+	Expect(cb.State()).To(Equal(CircuitClosed))
+
+	breakerCall(tr, cb.Wrap(failingFunc), true)
+	Expect(cb.State()).To(Equal(CircuitOpen))
+}
+
+func (s *BreakerSuite) TestReset(t sweet.T) {
+	changes := &stateChanges{}
+	cb := NewCircuitBreaker(BreakerPolicy{Window: 1, OpenTimeout: time.Hour, OnStateChange: changes.record})
+	tr := NewTaskRunner(WithClock(glock.NewMockClock()))
+
+	breakerCall(tr, cb.Wrap(failingFunc), true)
+	Expect(cb.State()).To(Equal(CircuitOpen))
+
+	cb.Reset()
+	Expect(cb.State()).To(Equal(CircuitClosed))
+	Expect(breakerCall(tr, cb.Wrap(failingFunc), false).Err()).To(BeNil())
+	Expect(changes.Changes()).To(Equal([]string{"closed -> open", "open -> closed"}))
+}
+
+func (s *BreakerSuite) TestStateString(t sweet.T) {
+	Expect(CircuitClosed.String()).To(Equal("closed"))
+	Expect(CircuitOpen.String()).To(Equal("open"))
+	Expect(CircuitHalfOpen.String()).To(Equal("half-open"))
+	Expect(CircuitState(100).String()).To(Equal("unknown"))
+}
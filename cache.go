@@ -0,0 +1,245 @@
+package boom
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// CachePolicy determines how long a ResultCache keeps results.
+type CachePolicy struct {
+	// TTL is how long a result is fresh after its task finishes. If TTL is 0
+	// results stay fresh until they're evicted.
+	TTL time.Duration
+	// MaxEntries is the number of results kept before the least recently used
+	// result is evicted. If MaxEntries is 0 the number of results isn't limited.
+	MaxEntries int
+	// ErrorTTL is how long results with an error are cached. If ErrorTTL is 0
+	// results with an error aren't cached.
+	ErrorTTL time.Duration
+	// StaleTTL is how long after a result stops being fresh it may still be
+	// returned while a task refreshes it in the background. If StaleTTL is 0
+	// results that aren't fresh are never returned.
+	StaleTTL time.Duration
+}
+
+// ResultCache caches the results of tasks by key. Tasks are run using a
+// DedupRunner, so concurrent requests for a key that isn't cached share a
+// single task.
+type ResultCache struct {
+	dedup  *DedupRunner
+	policy CachePolicy
+
+	lock    sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+
+	// current holds the task whose result will be stored for each key. Once
+	// it's stored, or the key is invalidated, the results of other tasks for
+	// the key are dropped so older results can't replace newer ones.
+	current map[string]*Task
+}
+
+type cacheEntry struct {
+	key    string
+	result TaskResult
+
+	expires    time.Time
+	staleUntil time.Time
+	refreshing bool
+}
+
+// NewResultCache creates a new ResultCache using the given policy. The configs
+// are applied to every task the cache runs, and the cache's TTLs are measured
+// with the configured clock.
+func NewResultCache(policy CachePolicy, configs ...TaskConfig) *ResultCache {
+	return &ResultCache{
+		dedup:   NewDedupRunner(configs...),
+		policy:  policy,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+		current: make(map[string]*Task),
+	}
+}
+
+func (c *ResultCache) now() time.Time {
+	return c.dedup.runner.cfg.clock.Now()
+}
+
+// Get returns the cached result for key. If there's no fresh result, f is run with
+// args to get one, unless a stale result can be returned while f refreshes it in
+// the background.
+func (c *ResultCache) Get(key string, f TaskFunc, args ...interface{}) TaskResult {
+	res, _ := c.GetWithContext(context.Background(), key, f, args...)
+	return res
+}
+
+// GetWithContext calls Get, giving up on waiting for f if ctx is done. The task
+// running f is only stopped once every caller waiting for it has given up.
+func (c *ResultCache) GetWithContext(ctx context.Context, key string, f TaskFunc, args ...interface{}) (TaskResult, error) {
+	res, handle := c.lookup(ctx, key, f, args...)
+	if handle == nil {
+		return res, nil
+	}
+
+	select {
+	case <-handle.Done():
+	case <-ctx.Done():
+		go c.release(key, handle)
+		return nil, ctx.Err()
+	}
+
+	res, _ = handle.Wait(0)
+	c.store(key, handle.Task(), res)
+
+	return res, nil
+}
+
+// lookup returns the cached result for key if it's fresh or stale. When a stale
+// result is returned, a refresh is started if one isn't already running. If
+// there's no result, f is run for key and the handle for its task is returned.
+func (c *ResultCache) lookup(ctx context.Context, key string, f TaskFunc, args ...interface{}) (TaskResult, *SharedTask) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*cacheEntry)
+
+		now := c.now()
+		if entry.expires.IsZero() || now.Before(entry.expires) {
+			c.lru.MoveToFront(elem)
+			return entry.result, nil
+		}
+
+		if now.Before(entry.staleUntil) {
+			c.lru.MoveToFront(elem)
+			if !entry.refreshing {
+				entry.refreshing = true
+				go c.refresh(entry, c.run(context.Background(), key, f, args...))
+			}
+			return entry.result, nil
+		}
+
+		c.remove(elem)
+	}
+
+	return nil, c.run(ctx, key, f, args...)
+}
+
+// run runs f for key using the cache's DedupRunner. A new task becomes the
+// current task for key, so its result is the one stored. The cache's lock must
+// be held.
+func (c *ResultCache) run(ctx context.Context, key string, f TaskFunc, args ...interface{}) *SharedTask {
+	handle := c.dedup.DoWithContext(ctx, key, f, args...)
+	if !handle.Shared() {
+		c.current[key] = handle.Task()
+	}
+	return handle
+}
+
+// refresh waits for the task refreshing the stale entry and stores its result.
+// If the task fails, the stale result is kept until it expires.
+func (c *ResultCache) refresh(entry *cacheEntry, handle *SharedTask) {
+	res, _ := handle.Wait(0)
+	if res == nil || res.Err() == nil {
+		c.store(entry.key, handle.Task(), res)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	entry.refreshing = false
+	c.forget(entry.key, handle.Task())
+}
+
+// release waits for the task of a handle whose caller gave up, so the task
+// stops being the current task for key if no other caller stored its result
+func (c *ResultCache) release(key string, handle *SharedTask) {
+	<-handle.Done()
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.forget(key, handle.Task())
+}
+
+// forget removes task as the current task for key, if it still is. The
+// cache's lock must be held.
+func (c *ResultCache) forget(key string, task *Task) {
+	if c.current[key] == task {
+		delete(c.current, key)
+	}
+}
+
+// store caches the result of task for key, if the policy allows it. Every
+// caller sharing a task stores its result, so it's only stored if task is
+// still the current task for key. This keeps the result from having its TTL
+// extended, or from replacing a newer result after a refresh or Invalidate.
+func (c *ResultCache) store(key string, task *Task, res TaskResult) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.current[key] != task {
+		return
+	}
+	delete(c.current, key)
+
+	ttl := c.policy.TTL
+	if res != nil && res.Err() != nil {
+		if c.policy.ErrorTTL <= 0 {
+			return
+		}
+		ttl = c.policy.ErrorTTL
+	}
+
+	entry := &cacheEntry{
+		key:    key,
+		result: res,
+	}
+	if ttl > 0 {
+		entry.expires = c.now().Add(ttl)
+		entry.staleUntil = entry.expires.Add(c.policy.StaleTTL)
+	}
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = entry
+		c.lru.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.lru.PushFront(entry)
+
+	if c.policy.MaxEntries > 0 {
+		for c.lru.Len() > c.policy.MaxEntries {
+			c.remove(c.lru.Back())
+		}
+	}
+}
+
+// remove removes an entry. The cache's lock must be held.
+func (c *ResultCache) remove(elem *list.Element) {
+	c.lru.Remove(elem)
+	delete(c.entries, elem.Value.(*cacheEntry).key)
+}
+
+// Invalidate removes the cached result for key, so the next Get runs a new task.
+func (c *ResultCache) Invalidate(key string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
+	}
+	delete(c.current, key)
+	c.dedup.Forget(key)
+}
+
+// Len returns the number of cached results, including those that are stale
+// or expired but haven't been removed yet.
+func (c *ResultCache) Len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.lru.Len()
+}
@@ -0,0 +1,312 @@
+package boom
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/aphistic/sweet"
+	"github.com/efritz/glock"
+	. "github.com/onsi/gomega"
+)
+
+type CacheSuite struct{}
+
+// countingValue returns a TaskFunc that counts its calls and returns the
+// number of the call as its value.
+func countingValue(calls *int32) TaskFunc {
+	return func(task *Task, args ...interface{}) TaskResult {
+		return NewValueResult(int(atomic.AddInt32(calls, 1)), nil)
+	}
+}
+
+func (s *CacheSuite) TestCached(t sweet.T) {
+	clock := glock.NewMockClock()
+	c := NewResultCache(CachePolicy{TTL: time.Minute}, WithClock(clock))
+
+	var calls int32
+	Expect(c.Get("key", countingValue(&calls))).To(Equal(NewValueResult(1, nil)))
+	Expect(c.Get("key", countingValue(&calls))).To(Equal(NewValueResult(1, nil)))
+	Expect(c.Get("other", countingValue(&calls))).To(Equal(NewValueResult(2, nil)))
+	Expect(c.Len()).To(Equal(2))
+
+	clock.Advance(59 * time.Second)
+	Expect(c.Get("key", countingValue(&calls))).To(Equal(NewValueResult(1, nil)))
+
+	clock.Advance(time.Second)
+	Expect(c.Get("key", countingValue(&calls))).To(Equal(NewValueResult(3, nil)))
+}
+
+func (s *CacheSuite) TestNoTTL(t sweet.T) {
+	clock := glock.NewMockClock()
+	c := NewResultCache(CachePolicy{}, WithClock(clock))
+
+	var calls int32
+	c.Get("key", countingValue(&calls))
+	clock.Advance(time.Hour)
+	Expect(c.Get("key", countingValue(&calls))).To(Equal(NewValueResult(1, nil)))
+}
+
+func (s *CacheSuite) TestShared(t sweet.T) {
+	c := NewResultCache(CachePolicy{TTL: time.Minute})
+
+	var calls int32
+	release := make(chan struct{})
+	f := func(task *Task, args ...interface{}) TaskResult {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return NewValueResult("value", nil)
+	}
+
+	results := make(chan TaskResult, 3)
+	for i := 0; i < 3; i++ {
+		go func() {
+			results <- c.Get("key", f)
+		}()
+	}
+
+	Eventually(func() int32 { return atomic.LoadInt32(&calls) }).Should(Equal(int32(1)))
+	close(release)
+
+	for i := 0; i < 3; i++ {
+		Eventually(results).Should(Receive(Equal(NewValueResult("value", nil))))
+	}
+	Expect(atomic.LoadInt32(&calls)).To(Equal(int32(1)))
+	Expect(c.Len()).To(Equal(1))
+}
+
+func (s *CacheSuite) TestLRU(t sweet.T) {
+	c := NewResultCache(CachePolicy{MaxEntries: 2})
+
+	var calls int32
+	c.Get("a", countingValue(&calls))
+	c.Get("b", countingValue(&calls))
+	c.Get("a", countingValue(&calls))
+	c.Get("c", countingValue(&calls))
+	Expect(c.Len()).To(Equal(2))
+
+	// b was the least recently used so it was evicted
+	Expect(c.Get("a", countingValue(&calls))).To(Equal(NewValueResult(1, nil)))
+	Expect(c.Get("b", countingValue(&calls))).To(Equal(NewValueResult(4, nil)))
+}
+
+func (s *CacheSuite) TestErrors(t sweet.T) {
+	var calls int32
+	f := func(task *Task, args ...interface{}) TaskResult {
+		atomic.AddInt32(&calls, 1)
+		return NewErrorResult(errors.New("failed"))
+	}
+
+	c := NewResultCache(CachePolicy{TTL: time.Minute})
+	c.Get("key", f)
+	c.Get("key", f)
+	Expect(atomic.LoadInt32(&calls)).To(Equal(int32(2)))
+	Expect(c.Len()).To(Equal(0))
+}
+
+func (s *CacheSuite) TestNegativeCaching(t sweet.T) {
+	clock := glock.NewMockClock()
+	c := NewResultCache(CachePolicy{TTL: time.Minute, ErrorTTL: time.Second}, WithClock(clock))
+
+	var calls int32
+	f := func(task *Task, args ...interface{}) TaskResult {
+		atomic.AddInt32(&calls, 1)
+		return NewErrorResult(errors.New("failed"))
+	}
+
+	Expect(c.Get("key", f).Err()).To(MatchError("failed"))
+	Expect(c.Get("key", f).Err()).To(MatchError("failed"))
+	Expect(atomic.LoadInt32(&calls)).To(Equal(int32(1)))
+
+	clock.Advance(time.Second)
+	c.Get("key", f)
+	Expect(atomic.LoadInt32(&calls)).To(Equal(int32(2)))
+}
+
+func (s *CacheSuite) TestStaleWhileRevalidate(t sweet.T) {
+	clock := glock.NewMockClock()
+	c := NewResultCache(CachePolicy{TTL: time.Minute, StaleTTL: time.Minute}, WithClock(clock))
+
+	var calls int32
+	release := make(chan struct{}, 1)
+	f := func(task *Task, args ...interface{}) TaskResult {
+		n := int(atomic.AddInt32(&calls, 1))
+		if n > 1 {
+			<-release
+		}
+		return NewValueResult(n, nil)
+	}
+
+	Expect(c.Get("key", f)).To(Equal(NewValueResult(1, nil)))
+
+	clock.Advance(time.Minute)
+
+	// The stale result is returned while a single refresh runs
+	Expect(c.Get("key", f)).To(Equal(NewValueResult(1, nil)))
+	Expect(c.Get("key", f)).To(Equal(NewValueResult(1, nil)))
+	Eventually(func() int32 { return atomic.LoadInt32(&calls) }).Should(Equal(int32(2)))
+
+	release <- struct{}{}
+	Eventually(func() TaskResult { return c.Get("key", f) }).Should(Equal(NewValueResult(2, nil)))
+	Expect(atomic.LoadInt32(&calls)).To(Equal(int32(2)))
+}
+
+func (s *CacheSuite) TestStaleExpired(t sweet.T) {
+	clock := glock.NewMockClock()
+	c := NewResultCache(CachePolicy{TTL: time.Minute, StaleTTL: time.Minute}, WithClock(clock))
+
+	var calls int32
+	c.Get("key", countingValue(&calls))
+
+	clock.Advance(2 * time.Minute)
+	Expect(c.Get("key", countingValue(&calls))).To(Equal(NewValueResult(2, nil)))
+}
+
+func (s *CacheSuite) TestStaleRefreshError(t sweet.T) {
+	clock := glock.NewMockClock()
+	c := NewResultCache(CachePolicy{TTL: time.Minute, StaleTTL: time.Minute}, WithClock(clock))
+
+	var calls int32
+	f := func(task *Task, args ...interface{}) TaskResult {
+		if atomic.AddInt32(&calls, 1) > 1 {
+			return NewErrorResult(errors.New("failed"))
+		}
+		return NewValueResult("value", nil)
+	}
+
+	c.Get("key", f)
+	clock.Advance(time.Minute)
+
+	Expect(c.Get("key", f)).To(Equal(NewValueResult("value", nil)))
+	Eventually(func() int32 { return atomic.LoadInt32(&calls) }).Should(Equal(int32(2)))
+
+	// The stale result is kept and refreshed again
+	Eventually(func() int32 {
+		c.Get("key", f)
+		return atomic.LoadInt32(&calls)
+	}).Should(Equal(int32(3)))
+	Expect(c.Get("key", f)).To(Equal(NewValueResult("value", nil)))
+}
+
+func (s *CacheSuite) TestInvalidate(t sweet.T) {
+	c := NewResultCache(CachePolicy{})
+
+	var calls int32
+	c.Get("key", countingValue(&calls))
+	c.Invalidate("key")
+	Expect(c.Len()).To(Equal(0))
+	Expect(c.Get("key", countingValue(&calls))).To(Equal(NewValueResult(2, nil)))
+}
+
+func (s *CacheSuite) TestInvalidateRunning(t sweet.T) {
+	c := NewResultCache(CachePolicy{})
+
+	var calls int32
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	f := func(task *Task, args ...interface{}) TaskResult {
+		n := int(atomic.AddInt32(&calls, 1))
+		if n == 1 {
+			started <- struct{}{}
+			<-release
+		}
+		return NewValueResult(n, nil)
+	}
+
+	resChan := make(chan TaskResult, 1)
+	go func() {
+		resChan <- c.Get("key", f)
+	}()
+
+	<-started
+	c.Invalidate("key")
+	close(release)
+
+	// The result of the task running when the key was invalidated is
+	// returned to its caller but not cached
+	Eventually(resChan).Should(Receive(Equal(NewValueResult(1, nil))))
+	Expect(c.Len()).To(Equal(0))
+	Expect(c.Get("key", f)).To(Equal(NewValueResult(2, nil)))
+}
+
+func (s *CacheSuite) TestStoreOlderResult(t sweet.T) {
+	clock := glock.NewMockClock()
+	c := NewResultCache(CachePolicy{TTL: time.Minute, StaleTTL: time.Minute}, WithClock(clock))
+
+	var calls int32
+	tasks := make(chan *Task, 2)
+	f := func(task *Task, args ...interface{}) TaskResult {
+		tasks <- task
+		return NewValueResult(int(atomic.AddInt32(&calls, 1)), nil)
+	}
+
+	Expect(c.Get("key", f)).To(Equal(NewValueResult(1, nil)))
+	first := <-tasks
+
+	clock.Advance(time.Minute)
+	Expect(c.Get("key", f)).To(Equal(NewValueResult(1, nil)))
+	Eventually(func() TaskResult { return c.Get("key", f) }).Should(Equal(NewValueResult(2, nil)))
+
+	// A slow caller that shared the first task stores its result late
+	c.store("key", first, NewValueResult(1, nil))
+	Expect(c.Get("key", f)).To(Equal(NewValueResult(2, nil)))
+}
+
+func (s *CacheSuite) TestContext(t sweet.T) {
+	c := NewResultCache(CachePolicy{})
+
+	started := make(chan struct{})
+	stopped := make(chan struct{})
+	f := func(task *Task, args ...interface{}) TaskResult {
+		close(started)
+		<-task.Stopping()
+		close(stopped)
+		return NewErrorResult(ErrCancelled)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	res, err := c.GetWithContext(ctx, "key", f)
+	Expect(err).To(Equal(context.Canceled))
+	Expect(res).To(BeNil())
+	Eventually(stopped).Should(BeClosed())
+	Expect(c.Len()).To(Equal(0))
+	Eventually(func() int {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+		return len(c.current)
+	}).Should(BeZero())
+}
+
+func (s *CacheSuite) TestSharedRefreshDropped(t sweet.T) {
+	clock := glock.NewMockClock()
+	c := NewResultCache(CachePolicy{TTL: time.Minute, StaleTTL: time.Hour}, WithClock(clock))
+
+	var calls int32
+	Expect(c.Get("key", countingValue(&calls))).To(Equal(NewValueResult(1, nil)))
+	clock.Advance(time.Minute)
+
+	// A task for the key that isn't the cache's own is shared by the refresh,
+	// so its result isn't stored
+	release := make(chan struct{})
+	other := c.dedup.Do("key", func(task *Task, args ...interface{}) TaskResult {
+		<-release
+		return NewValueResult("other", nil)
+	})
+
+	Expect(c.Get("key", countingValue(&calls))).To(Equal(NewValueResult(1, nil)))
+	close(release)
+	other.Wait(0)
+
+	// The next stale lookup starts another refresh
+	Eventually(func() int32 {
+		c.Get("key", countingValue(&calls))
+		return atomic.LoadInt32(&calls)
+	}).Should(Equal(int32(2)))
+	Eventually(func() TaskResult { return c.Get("key", countingValue(&calls)) }).Should(Equal(NewValueResult(2, nil)))
+}
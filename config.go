@@ -1,13 +1,28 @@
 package boom
 
 import (
+	"time"
+
 	"github.com/efritz/glock"
 )
 
 type TaskConfig func(*taskConfig)
 
 type taskConfig struct {
-	clock glock.Clock
+	clock          glock.Clock
+	panicHandler   PanicHandler
+	maxConcurrency int
+	failPolicy     FailPolicy
+	retry          *RetryPolicy
+	taskTimeout    time.Duration
+	overflowPolicy OverflowPolicy
+	name           string
+	labels         map[string]string
+	hooks          []Hooks
+	tracer         Tracer
+	overlapPolicy  OverlapPolicy
+	jitter         time.Duration
+	rateLimiter    *RateLimiter
 }
 
 func newTaskConfig() *taskConfig {
@@ -22,8 +37,94 @@ func (tc *taskConfig) ApplyConfigs(configs []TaskConfig) {
 	}
 }
 
+// with returns a copy of the config with configs applied to it, leaving the
+// original config unchanged.
+func (tc *taskConfig) with(configs []TaskConfig) *taskConfig {
+	if len(configs) == 0 {
+		return tc
+	}
+
+	cfg := *tc
+	cfg.hooks = append([]Hooks{}, tc.hooks...)
+	cfg.labels = make(map[string]string, len(tc.labels))
+	for key, value := range tc.labels {
+		cfg.labels[key] = value
+	}
+
+	cfg.ApplyConfigs(configs)
+	return &cfg
+}
+
 func WithClock(clock glock.Clock) TaskConfig {
 	return func(cfg *taskConfig) {
 		cfg.clock = clock
 	}
 }
+
+// PanicHandler is the signature for the function called when a task's function
+// panics. It is called from the task's goroutine before the PanicResult is
+// returned from the task, so it may re-panic if the panic should not be recovered.
+type PanicHandler func(task *Task, result *PanicResult)
+
+// WithPanicHandler sets a function to be called when a task's function panics.
+func WithPanicHandler(handler PanicHandler) TaskConfig {
+	return func(cfg *taskConfig) {
+		cfg.panicHandler = handler
+	}
+}
+
+// WithMaxConcurrency limits the number of tasks an AsyncCollector executes at
+// the same time. Tasks run beyond the limit are queued and started in the order
+// they were added as running tasks finish. A value of 0 means no limit.
+func WithMaxConcurrency(n int) TaskConfig {
+	return func(cfg *taskConfig) {
+		cfg.maxConcurrency = n
+	}
+}
+
+// WithFailFast causes an AsyncCollector to stop waiting as soon as the results of
+// its tasks trip the given FailPolicy, cancelling any tasks that are still running.
+func WithFailFast(policy FailPolicy) TaskConfig {
+	return func(cfg *taskConfig) {
+		cfg.failPolicy = policy
+	}
+}
+
+// WithTaskTimeout limits how long each task may execute once it has started.
+// The task's context reports the deadline, measured using the configured
+// clock, and if the task hasn't finished by then it's cancelled and its result
+// is an ErrorResult with ErrDeadlineExceeded. The task doesn't finish until its
+// function returns, so functions should stop once their context is done.
+func WithTaskTimeout(timeout time.Duration) TaskConfig {
+	return func(cfg *taskConfig) {
+		cfg.taskTimeout = timeout
+	}
+}
+
+// WithOverflowPolicy sets what a PoolRunner does when a task is submitted
+// while its queue is full. The default is OverflowBlock.
+func WithOverflowPolicy(policy OverflowPolicy) TaskConfig {
+	return func(cfg *taskConfig) {
+		cfg.overflowPolicy = policy
+	}
+}
+
+// WithName sets the name of tasks, which can be used to identify them when debugging.
+func WithName(name string) TaskConfig {
+	return func(cfg *taskConfig) {
+		cfg.name = name
+	}
+}
+
+// WithLabels adds key/value labels to tasks, which can be used to identify
+// them when debugging.
+func WithLabels(labels map[string]string) TaskConfig {
+	return func(cfg *taskConfig) {
+		if cfg.labels == nil {
+			cfg.labels = make(map[string]string, len(labels))
+		}
+		for key, value := range labels {
+			cfg.labels[key] = value
+		}
+	}
+}
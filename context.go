@@ -0,0 +1,94 @@
+package boom
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// taskContext is the context given to a task. It behaves like the context it
+// wraps, except that it reports the task's deadline once one has been set and
+// returns context.DeadlineExceeded from Err if the task was cancelled because
+// its deadline passed. Values are looked up in the values context instead, if
+// one has been set.
+type taskContext struct {
+	context.Context
+
+	lock     sync.RWMutex
+	deadline time.Time
+	exceeded bool
+	values   context.Context
+}
+
+func newTaskContext(ctx context.Context) *taskContext {
+	return &taskContext{
+		Context: ctx,
+	}
+}
+
+func (c *taskContext) Deadline() (time.Time, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	parent, ok := c.Context.Deadline()
+	if c.deadline.IsZero() || (ok && parent.Before(c.deadline)) {
+		return parent, ok
+	}
+	return c.deadline, true
+}
+
+func (c *taskContext) Err() error {
+	select {
+	case <-c.Done():
+	default:
+		return nil
+	}
+
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.exceeded {
+		return context.DeadlineExceeded
+	}
+	return c.Context.Err()
+}
+
+func (c *taskContext) setDeadline(deadline time.Time) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.deadline = deadline
+}
+
+// setExceeded marks the context's deadline as exceeded if the context isn't
+// already done. The context still needs to be cancelled afterward.
+func (c *taskContext) setExceeded() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	select {
+	case <-c.Done():
+	default:
+		c.exceeded = true
+	}
+}
+
+func (c *taskContext) Value(key interface{}) interface{} {
+	c.lock.RLock()
+	values := c.values
+	c.lock.RUnlock()
+
+	if values != nil {
+		return values.Value(key)
+	}
+	return c.Context.Value(key)
+}
+
+// setValues sets the context values are looked up in. It should be derived
+// from the wrapped context so values from the parent are still available.
+func (c *taskContext) setValues(values context.Context) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.values = values
+}
@@ -0,0 +1,238 @@
+package boom
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronTrigger is a Trigger that fires at the times matched by a cron expression
+type cronTrigger struct {
+	minute uint64
+	hour   uint64
+	dom    uint64
+	month  uint64
+	dow    uint64
+
+	// domAny and dowAny are set if the day of month or day of week field is
+	// '*', which changes how days are matched
+	domAny bool
+	dowAny bool
+}
+
+type cronField struct {
+	name  string
+	min   int
+	max   int
+	names map[string]int
+}
+
+var (
+	cronMinute = cronField{name: "minute", min: 0, max: 59}
+	cronHour   = cronField{name: "hour", min: 0, max: 23}
+	cronDOM    = cronField{name: "day of month", min: 1, max: 31}
+	cronMonth  = cronField{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// Day of week allows 7 as well as 0 for Sunday
+	cronDOW = cronField{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+
+	cronDescriptors = map[string]string{
+		"@yearly":   "0 0 1 1 *",
+		"@annually": "0 0 1 1 *",
+		"@monthly":  "0 0 1 * *",
+		"@weekly":   "0 0 * * 0",
+		"@daily":    "0 0 * * *",
+		"@midnight": "0 0 * * *",
+		"@hourly":   "0 * * * *",
+	}
+)
+
+// cronSearchLimit is how far ahead Next looks for a matching time before
+// deciding the expression never matches, such as "0 0 30 2 *"
+const cronSearchLimit = 5 * 366 * 24 * time.Hour
+
+// Cron parses a standard five field cron expression, "minute hour day-of-month
+// month day-of-week", and returns a Trigger that fires at the times it matches
+// in the location of the time given to Next. Fields may be '*', a value, a range
+// such as "1-5", a step such as "*/15" or "0-30/10", or a comma separated list
+// of these. Months and days of the week may also be given by their three letter
+// names. The descriptors @yearly, @annually, @monthly, @weekly, @daily, @midnight
+// and @hourly are also supported.
+//
+// As with cron, if both the day of month and day of week are restricted, a
+// time matches if either of them match.
+func Cron(expr string) (Trigger, error) {
+	spec := strings.TrimSpace(expr)
+	if descriptor, ok := cronDescriptors[strings.ToLower(spec)]; ok {
+		spec = descriptor
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, &CronSyntaxError{Expr: expr, Reason: "expected 5 fields"}
+	}
+
+	var (
+		trigger = &cronTrigger{}
+		err     error
+	)
+
+	parsers := []struct {
+		field cronField
+		dst   *uint64
+	}{
+		{cronMinute, &trigger.minute},
+		{cronHour, &trigger.hour},
+		{cronDOM, &trigger.dom},
+		{cronMonth, &trigger.month},
+		{cronDOW, &trigger.dow},
+	}
+	for idx, parser := range parsers {
+		*parser.dst, err = parser.field.parse(fields[idx])
+		if err != nil {
+			return nil, &CronSyntaxError{Expr: expr, Reason: err.Error()}
+		}
+	}
+
+	// Sunday can be given as 0 or 7
+	if trigger.dow&(1<<7) != 0 {
+		trigger.dow |= 1
+	}
+
+	trigger.domAny = fields[2] == "*"
+	trigger.dowAny = fields[4] == "*"
+
+	return trigger, nil
+}
+
+// MustCron is like Cron but panics if the expression can't be parsed.
+func MustCron(expr string) Trigger {
+	trigger, err := Cron(expr)
+	if err != nil {
+		panic(err)
+	}
+	return trigger
+}
+
+// parse parses a field into a bit set of the values it matches
+func (f cronField) parse(field string) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if idx := strings.IndexByte(part, '/'); idx >= 0 {
+			rangePart = part[:idx]
+
+			var err error
+			step, err = strconv.Atoi(part[idx+1:])
+			if err != nil || step <= 0 {
+				return 0, cronFieldError(f, "invalid step in %q", part)
+			}
+		}
+
+		var low, high int
+		switch {
+		case rangePart == "*":
+			low, high = f.min, f.max
+		case strings.IndexByte(rangePart, '-') >= 0:
+			idx := strings.IndexByte(rangePart, '-')
+
+			var err error
+			if low, err = f.value(rangePart[:idx]); err != nil {
+				return 0, err
+			}
+			if high, err = f.value(rangePart[idx+1:]); err != nil {
+				return 0, err
+			}
+			if low > high {
+				return 0, cronFieldError(f, "invalid range %q", rangePart)
+			}
+		default:
+			var err error
+			if low, err = f.value(rangePart); err != nil {
+				return 0, err
+			}
+
+			// A single value with a step, such as "5/10", means from the
+			// value through the maximum.
+			high = low
+			if rangePart != part {
+				high = f.max
+			}
+		}
+
+		for value := low; value <= high; value += step {
+			bits |= 1 << uint(value)
+		}
+	}
+
+	return bits, nil
+}
+
+// value parses a single value or name in a field
+func (f cronField) value(value string) (int, error) {
+	if n, ok := f.names[strings.ToLower(value)]; ok {
+		return n, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, cronFieldError(f, "invalid value %q", value)
+	}
+	if n < f.min || n > f.max {
+		return 0, cronFieldError(f, "value %d out of range %d-%d", n, f.min, f.max)
+	}
+
+	return n, nil
+}
+
+func cronFieldError(f cronField, format string, args ...interface{}) error {
+	return fmt.Errorf("%s: %s", f.name, fmt.Sprintf(format, args...))
+}
+
+// Next returns the first time after t that matches the expression
+func (c *cronTrigger) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(cronSearchLimit)
+
+	for t.Before(limit) {
+		if c.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if !c.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if c.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if c.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+func (c *cronTrigger) matchDay(t time.Time) bool {
+	dom := c.dom&(1<<uint(t.Day())) != 0
+	dow := c.dow&(1<<uint(t.Weekday())) != 0
+
+	if c.domAny || c.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
@@ -0,0 +1,92 @@
+package boom
+
+import (
+	"time"
+
+	"github.com/aphistic/sweet"
+	. "github.com/onsi/gomega"
+)
+
+type CronSuite struct{}
+
+func cronTime(value string) time.Time {
+	t, err := time.Parse("2006-01-02 15:04", value)
+	Expect(err).To(BeNil())
+	return t
+}
+
+func (s *CronSuite) TestNext(t sweet.T) {
+	cases := []struct {
+		expr string
+		from string
+		next string
+	}{
+		{"* * * * *", "2020-01-01 10:00", "2020-01-01 10:01"},
+		{"*/15 * * * *", "2020-01-01 10:07", "2020-01-01 10:15"},
+		{"0 * * * *", "2020-01-01 10:00", "2020-01-01 11:00"},
+		{"30 9 * * *", "2020-01-01 10:00", "2020-01-02 09:30"},
+		{"0 9-17/4 * * *", "2020-01-01 10:00", "2020-01-01 13:00"},
+		{"5,10 0 * * *", "2020-01-01 00:07", "2020-01-01 00:10"},
+		{"0 0 1 * *", "2020-01-15 00:00", "2020-02-01 00:00"},
+		{"0 0 31 * *", "2020-02-01 00:00", "2020-03-31 00:00"},
+		{"0 0 29 feb *", "2021-01-01 00:00", "2024-02-29 00:00"},
+		{"0 0 * * mon-fri", "2020-01-03 12:00", "2020-01-06 00:00"},
+		{"0 0 * * 7", "2020-01-01 00:00", "2020-01-05 00:00"},
+		{"0 0 13 * fri", "2020-01-01 00:00", "2020-01-03 00:00"},
+		{"0 0 13 * fri", "2020-01-10 00:00", "2020-01-13 00:00"},
+		{"10/20 * * * *", "2020-01-01 10:31", "2020-01-01 10:50"},
+		{"@hourly", "2020-01-01 10:30", "2020-01-01 11:00"},
+		{"@daily", "2020-01-01 10:30", "2020-01-02 00:00"},
+		{"@weekly", "2020-01-01 10:30", "2020-01-05 00:00"},
+		{"@yearly", "2020-01-01 10:30", "2021-01-01 00:00"},
+	}
+
+	for _, c := range cases {
+		trigger, err := Cron(c.expr)
+		Expect(err).To(BeNil())
+		Expect(trigger.Next(cronTime(c.from))).To(Equal(cronTime(c.next)), c.expr)
+	}
+}
+
+func (s *CronSuite) TestNextSeconds(t sweet.T) {
+	trigger := MustCron("* * * * *")
+	from := cronTime("2020-01-01 10:00").Add(30 * time.Second)
+	Expect(trigger.Next(from)).To(Equal(cronTime("2020-01-01 10:01")))
+}
+
+func (s *CronSuite) TestNextLocation(t sweet.T) {
+	loc := time.FixedZone("IST", 5*60*60+30*60)
+	trigger := MustCron("0 * * * *")
+	from := time.Date(2020, 1, 1, 10, 10, 0, 0, loc)
+	Expect(trigger.Next(from)).To(Equal(time.Date(2020, 1, 1, 11, 0, 0, 0, loc)))
+}
+
+func (s *CronSuite) TestNeverMatches(t sweet.T) {
+	trigger := MustCron("0 0 30 2 *")
+	Expect(trigger.Next(cronTime("2020-01-01 00:00")).IsZero()).To(BeTrue())
+}
+
+func (s *CronSuite) TestInvalid(t sweet.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"* * * foo *",
+		"@every",
+	} {
+		_, err := Cron(expr)
+		Expect(err).To(BeAssignableToTypeOf(&CronSyntaxError{}), expr)
+	}
+}
+
+func (s *CronSuite) TestMustCronPanics(t sweet.T) {
+	Expect(func() { MustCron("bad") }).To(Panic())
+}
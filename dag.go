@@ -0,0 +1,221 @@
+package boom
+
+import (
+	"context"
+)
+
+// DAGFunc is the signature for the function executed for a node in a DAG. inputs
+// contains the results of the node's dependencies keyed by the dependency's name.
+type DAGFunc func(task *Task, inputs map[string]TaskResult) TaskResult
+
+// DAG executes a set of named tasks where each task may depend on the results of
+// other tasks. Tasks are executed in parallel as soon as all of their dependencies
+// have finished successfully.
+type DAG struct {
+	cfg   *taskConfig
+	nodes map[string]*dagNode
+	order []string
+}
+
+type dagNode struct {
+	name string
+	deps []string
+	f    DAGFunc
+}
+
+type dagResult struct {
+	name   string
+	result TaskResult
+}
+
+// NewDAG creates a new DAG instance
+func NewDAG(configs ...TaskConfig) *DAG {
+	cfg := newTaskConfig()
+	cfg.ApplyConfigs(configs)
+
+	return &DAG{
+		cfg:   cfg,
+		nodes: make(map[string]*dagNode),
+		order: make([]string, 0),
+	}
+}
+
+// Add adds a node to the DAG with the given name, function and the names of the
+// nodes it depends on. Dependencies don't need to be added before the nodes that
+// depend on them. ErrDuplicateNode is returned if a node with the name already exists.
+func (d *DAG) Add(name string, f DAGFunc, deps ...string) error {
+	if _, ok := d.nodes[name]; ok {
+		return ErrDuplicateNode
+	}
+
+	d.nodes[name] = &dagNode{
+		name: name,
+		deps: deps,
+		f:    f,
+	}
+	d.order = append(d.order, name)
+
+	return nil
+}
+
+// Validate checks that every dependency in the DAG exists and that there are
+// no cycles between nodes, returning a *MissingDependencyError or a *CycleError
+// if there are.
+func (d *DAG) Validate() error {
+	for _, name := range d.order {
+		for _, dep := range d.nodes[name].deps {
+			if _, ok := d.nodes[dep]; !ok {
+				return &MissingDependencyError{Node: name, Dependency: dep}
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make(map[string]int, len(d.nodes))
+	var path []string
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			// Trim the path down to the start of the cycle
+			for idx, pathName := range path {
+				if pathName == name {
+					cycle := append([]string{}, path[idx:]...)
+					return &CycleError{Path: append(cycle, name)}
+				}
+			}
+		}
+
+		state[name] = visiting
+		path = append(path, name)
+		for _, dep := range d.nodes[name].deps {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = visited
+
+		return nil
+	}
+
+	for _, name := range d.order {
+		if err := visit(name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Run validates the DAG and then executes its nodes, returning the result of
+// each node keyed by name once every node has finished. If a node's dependency
+// fails, the node isn't executed and its result is an ErrorResult with an
+// *UpstreamError. If ctx is done, running nodes are stopped and nodes that
+// haven't started are given an ErrorResult with ErrCancelled.
+func (d *DAG) Run(ctx context.Context) (map[string]TaskResult, error) {
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]TaskResult, len(d.nodes))
+	waiting := make(map[string]int, len(d.nodes))
+	dependents := make(map[string][]string, len(d.nodes))
+	ready := make([]string, 0)
+
+	for _, name := range d.order {
+		node := d.nodes[name]
+		waiting[name] = len(node.deps)
+		for _, dep := range node.deps {
+			dependents[dep] = append(dependents[dep], name)
+		}
+		if len(node.deps) == 0 {
+			ready = append(ready, name)
+		}
+	}
+
+	resChan := make(chan *dagResult, len(d.nodes))
+	running := 0
+
+	for len(results) < len(d.nodes) {
+		for len(ready) > 0 {
+			name := ready[0]
+			ready = ready[1:]
+
+			res := d.start(ctx, d.nodes[name], results, resChan)
+			if res == nil {
+				running++
+				continue
+			}
+
+			// The node wasn't started so it's finished right away
+			results[name] = res
+			ready = d.finished(name, dependents, waiting, ready)
+		}
+
+		if running == 0 {
+			break
+		}
+
+		res := <-resChan
+		running--
+		results[res.name] = res.result
+		ready = d.finished(res.name, dependents, waiting, ready)
+	}
+
+	return results, nil
+}
+
+// start executes the node as a task if all of its dependencies were successful
+// and ctx isn't done. If the node isn't executed, the result for the node is
+// returned instead.
+func (d *DAG) start(ctx context.Context, node *dagNode, results map[string]TaskResult, resChan chan<- *dagResult) TaskResult {
+	inputs := make(map[string]TaskResult, len(node.deps))
+	for _, dep := range node.deps {
+		res := results[dep]
+		if res != nil && res.Err() != nil {
+			return NewErrorResult(&UpstreamError{Dependency: dep, Err: res.Err()})
+		}
+		inputs[dep] = res
+	}
+
+	select {
+	case <-ctx.Done():
+		return NewErrorResult(ErrCancelled)
+	default:
+	}
+
+	task := runTask(ctx, d.cfg, func(task *Task, args ...interface{}) TaskResult {
+		return node.f(task, inputs)
+	})
+
+	go func() {
+		res, _ := task.Wait(0)
+		resChan <- &dagResult{
+			name:   node.name,
+			result: res,
+		}
+	}()
+
+	return nil
+}
+
+// finished updates the nodes depending on name and returns ready with the nodes
+// that no longer have any dependencies to wait for appended to it.
+func (d *DAG) finished(name string, dependents map[string][]string, waiting map[string]int, ready []string) []string {
+	for _, dependent := range dependents[name] {
+		waiting[dependent]--
+		if waiting[dependent] == 0 {
+			ready = append(ready, dependent)
+		}
+	}
+	return ready
+}
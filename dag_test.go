@@ -0,0 +1,144 @@
+package boom
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/aphistic/sweet"
+	. "github.com/onsi/gomega"
+)
+
+type DAGSuite struct{}
+
+func (s *DAGSuite) TestRun(t sweet.T) {
+	dag := NewDAG()
+
+	var lock sync.Mutex
+	order := []string{}
+	record := func(name string) {
+		lock.Lock()
+		defer lock.Unlock()
+		order = append(order, name)
+	}
+
+	Expect(dag.Add("c", func(task *Task, inputs map[string]TaskResult) TaskResult {
+		record("c")
+		a := inputs["a"].(*ValueResult).Value.(int)
+		b := inputs["b"].(*ValueResult).Value.(int)
+		return NewValueResult(a+b, nil)
+	}, "a", "b")).To(BeNil())
+	aDone := make(chan struct{})
+	Expect(dag.Add("a", func(task *Task, inputs map[string]TaskResult) TaskResult {
+		defer close(aDone)
+		record("a")
+		Expect(inputs).To(BeEmpty())
+		return NewValueResult(1, nil)
+	})).To(BeNil())
+	Expect(dag.Add("b", func(task *Task, inputs map[string]TaskResult) TaskResult {
+		<-aDone
+		record("b")
+		return NewValueResult(2, nil)
+	})).To(BeNil())
+
+	results, err := dag.Run(context.Background())
+	Expect(err).To(BeNil())
+	Expect(results).To(Equal(map[string]TaskResult{
+		"a": NewValueResult(1, nil),
+		"b": NewValueResult(2, nil),
+		"c": NewValueResult(3, nil),
+	}))
+	Expect(order).To(Equal([]string{"a", "b", "c"}))
+}
+
+func (s *DAGSuite) TestAddDuplicate(t sweet.T) {
+	dag := NewDAG()
+
+	f := func(task *Task, inputs map[string]TaskResult) TaskResult {
+		return nil
+	}
+	Expect(dag.Add("a", f)).To(BeNil())
+	Expect(dag.Add("a", f)).To(Equal(ErrDuplicateNode))
+}
+
+func (s *DAGSuite) TestValidateMissingDependency(t sweet.T) {
+	dag := NewDAG()
+
+	dag.Add("a", func(task *Task, inputs map[string]TaskResult) TaskResult {
+		return nil
+	}, "b")
+
+	err := dag.Validate()
+	Expect(err).To(Equal(&MissingDependencyError{Node: "a", Dependency: "b"}))
+
+	results, err := dag.Run(context.Background())
+	Expect(results).To(BeNil())
+	Expect(err).ToNot(BeNil())
+}
+
+func (s *DAGSuite) TestValidateCycle(t sweet.T) {
+	dag := NewDAG()
+
+	f := func(task *Task, inputs map[string]TaskResult) TaskResult {
+		return nil
+	}
+	dag.Add("a", f)
+	dag.Add("b", f, "a", "d")
+	dag.Add("c", f, "b")
+	dag.Add("d", f, "c")
+
+	err := dag.Validate()
+	Expect(err).To(Equal(&CycleError{Path: []string{"b", "d", "c", "b"}}))
+	Expect(err.Error()).To(Equal("Dependency cycle detected: b -> d -> c -> b"))
+}
+
+func (s *DAGSuite) TestRunPropagatesFailure(t sweet.T) {
+	dag := NewDAG()
+
+	ran := make(chan string, 3)
+	dag.Add("a", func(task *Task, inputs map[string]TaskResult) TaskResult {
+		ran <- "a"
+		return NewErrorResult(errors.New("I'm an error! - Ralph"))
+	})
+	dag.Add("b", func(task *Task, inputs map[string]TaskResult) TaskResult {
+		ran <- "b"
+		return NewValueResult(1, nil)
+	}, "a")
+	dag.Add("c", func(task *Task, inputs map[string]TaskResult) TaskResult {
+		ran <- "c"
+		return NewValueResult(1, nil)
+	}, "b")
+
+	results, err := dag.Run(context.Background())
+	Expect(err).To(BeNil())
+	Expect(results["a"]).To(Equal(NewErrorResult(errors.New("I'm an error! - Ralph"))))
+	Expect(results["b"]).To(Equal(NewErrorResult(&UpstreamError{
+		Dependency: "a",
+		Err:        errors.New("I'm an error! - Ralph"),
+	})))
+	Expect(results["c"].Err()).To(BeAssignableToTypeOf(&UpstreamError{}))
+
+	Expect(ran).To(Receive(Equal("a")))
+	Expect(ran).ToNot(Receive())
+}
+
+func (s *DAGSuite) TestRunCancelled(t sweet.T) {
+	dag := NewDAG()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	dag.Add("a", func(task *Task, inputs map[string]TaskResult) TaskResult {
+		cancel()
+		<-task.Stopping()
+		return NewValueResult(1, nil)
+	})
+	dag.Add("b", func(task *Task, inputs map[string]TaskResult) TaskResult {
+		return NewValueResult(2, nil)
+	}, "a")
+
+	results, err := dag.Run(ctx)
+	Expect(err).To(BeNil())
+	Expect(results).To(Equal(map[string]TaskResult{
+		"a": NewValueResult(1, nil),
+		"b": NewErrorResult(ErrCancelled),
+	}))
+}
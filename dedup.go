@@ -0,0 +1,182 @@
+package boom
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DedupRunner runs tasks keyed by a string so that only one task executes for
+// a key at a time. Callers that ask for a key while its task is executing share
+// the task and its result rather than starting another one.
+type DedupRunner struct {
+	runner *TaskRunner
+
+	lock  sync.Mutex
+	calls map[string]*dedupCall
+}
+
+// dedupCall is a task shared by the callers of a key
+type dedupCall struct {
+	key  string
+	task *Task
+
+	// waiters is the number of handles that haven't been abandoned. It's
+	// guarded by the runner's lock.
+	waiters int
+
+	result   TaskResult
+	doneChan chan struct{}
+}
+
+// SharedTask is a caller's handle to a task shared by a DedupRunner. Each call to
+// Do returns a new handle, even if the task is shared.
+type SharedTask struct {
+	runner *DedupRunner
+	call   *dedupCall
+	shared bool
+
+	abandonOnce sync.Once
+}
+
+// NewDedupRunner creates a new DedupRunner. The configs are applied to every task
+// it runs.
+func NewDedupRunner(configs ...TaskConfig) *DedupRunner {
+	return &DedupRunner{
+		runner: NewTaskRunner(configs...),
+		calls:  make(map[string]*dedupCall),
+	}
+}
+
+// Do runs f with args for key, unless a task for key is already executing, in
+// which case the returned handle shares that task instead.
+func (d *DedupRunner) Do(key string, f TaskFunc, args ...interface{}) *SharedTask {
+	return d.DoWithContext(context.Background(), key, f, args...)
+}
+
+// DoWithContext calls Do and abandons the returned handle when ctx is done. The
+// context isn't given to the task since it may be shared with other callers.
+func (d *DedupRunner) DoWithContext(ctx context.Context, key string, f TaskFunc, args ...interface{}) *SharedTask {
+	d.lock.Lock()
+
+	call, shared := d.calls[key]
+	if !shared {
+		call = &dedupCall{
+			key:      key,
+			task:     d.runner.Run(f, args...),
+			doneChan: make(chan struct{}),
+		}
+		d.calls[key] = call
+		go d.wait(call)
+	}
+	call.waiters++
+
+	d.lock.Unlock()
+
+	handle := &SharedTask{
+		runner: d,
+		call:   call,
+		shared: shared,
+	}
+
+	if ctx.Done() != nil {
+		go func() {
+			select {
+			case <-ctx.Done():
+				handle.Abandon()
+			case <-call.doneChan:
+			}
+		}()
+	}
+
+	return handle
+}
+
+// wait waits for the call's result and removes it from the runner so later
+// callers of the key start a new task
+func (d *DedupRunner) wait(call *dedupCall) {
+	res, _ := call.task.Wait(0)
+	call.result = res
+	close(call.doneChan)
+
+	d.forget(call)
+}
+
+// forget removes the call if it's still the one running for its key
+func (d *DedupRunner) forget(call *dedupCall) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if d.calls[call.key] == call {
+		delete(d.calls, call.key)
+	}
+}
+
+// Forget makes later calls to Do for key start a new task, even if the current
+// task for key is still executing. Callers already sharing the current task
+// still receive its result.
+func (d *DedupRunner) Forget(key string) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	delete(d.calls, key)
+}
+
+// Task returns the shared task
+func (s *SharedTask) Task() *Task {
+	return s.call.task
+}
+
+// Shared returns true if the handle joined a task that was already executing
+// for its key rather than starting a new one.
+func (s *SharedTask) Shared() bool {
+	return s.shared
+}
+
+// Done returns a channel that is closed once the shared task's result is available.
+func (s *SharedTask) Done() <-chan struct{} {
+	return s.call.doneChan
+}
+
+// Wait waits for the shared task to finish and returns its result. Wait can be
+// called from any number of handles sharing the task. If a non-zero timeout is
+// provided and the task hasn't finished by then, ErrTimeout is returned.
+func (s *SharedTask) Wait(timeout time.Duration) (TaskResult, error) {
+	var timeoutChan <-chan time.Time
+	if timeout > 0 {
+		timeoutChan = s.call.task.cfg.clock.After(timeout)
+	}
+
+	select {
+	case <-s.call.doneChan:
+		return s.call.result, nil
+	case <-timeoutChan:
+		return nil, ErrTimeout
+	}
+}
+
+// Abandon gives up on the shared task's result. Once every handle sharing the
+// task has abandoned it, the task is stopped and its key is forgotten.
+func (s *SharedTask) Abandon() {
+	s.abandonOnce.Do(func() {
+		d := s.runner
+
+		d.lock.Lock()
+		s.call.waiters--
+		abandoned := s.call.waiters == 0
+		if abandoned && d.calls[s.call.key] == s.call {
+			delete(d.calls, s.call.key)
+		}
+		d.lock.Unlock()
+
+		if !abandoned {
+			return
+		}
+
+		select {
+		case <-s.call.doneChan:
+		default:
+			s.call.task.Stop()
+		}
+	})
+}
@@ -0,0 +1,162 @@
+package boom
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/aphistic/sweet"
+	"github.com/efritz/glock"
+	. "github.com/onsi/gomega"
+)
+
+type DedupSuite struct{}
+
+// blockingCounter returns a TaskFunc that counts its calls and returns its
+// first argument once release is closed or it's stopped.
+func blockingCounter(calls *int32, release <-chan struct{}) TaskFunc {
+	return func(task *Task, args ...interface{}) TaskResult {
+		atomic.AddInt32(calls, 1)
+		select {
+		case <-release:
+			return NewValueResult(args[0], nil)
+		case <-task.Stopping():
+			return NewErrorResult(ErrCancelled)
+		}
+	}
+}
+
+func (s *DedupSuite) TestShared(t sweet.T) {
+	d := NewDedupRunner()
+
+	var calls int32
+	release := make(chan struct{})
+
+	first := d.Do("key", blockingCounter(&calls, release), 1)
+	second := d.Do("key", blockingCounter(&calls, release), 2)
+	other := d.Do("other", blockingCounter(&calls, release), 3)
+
+	Expect(first.Shared()).To(BeFalse())
+	Expect(second.Shared()).To(BeTrue())
+	Expect(other.Shared()).To(BeFalse())
+	Expect(second.Task()).To(BeIdenticalTo(first.Task()))
+
+	close(release)
+
+	for _, handle := range []*SharedTask{first, second} {
+		res, err := handle.Wait(0)
+		Expect(err).To(BeNil())
+		Expect(res).To(Equal(NewValueResult(1, nil)))
+	}
+
+	res, err := other.Wait(0)
+	Expect(err).To(BeNil())
+	Expect(res).To(Equal(NewValueResult(3, nil)))
+	Expect(atomic.LoadInt32(&calls)).To(Equal(int32(2)))
+}
+
+func (s *DedupSuite) TestNewTaskAfterFinish(t sweet.T) {
+	d := NewDedupRunner()
+
+	var calls int32
+	release := make(chan struct{})
+	close(release)
+
+	first := d.Do("key", blockingCounter(&calls, release), 1)
+	Eventually(first.Done()).Should(BeClosed())
+	Eventually(func() int { d.lock.Lock(); defer d.lock.Unlock(); return len(d.calls) }).Should(Equal(0))
+
+	second := d.Do("key", blockingCounter(&calls, release), 2)
+	Expect(second.Shared()).To(BeFalse())
+
+	res, _ := second.Wait(0)
+	Expect(res).To(Equal(NewValueResult(2, nil)))
+	Expect(atomic.LoadInt32(&calls)).To(Equal(int32(2)))
+}
+
+func (s *DedupSuite) TestForget(t sweet.T) {
+	d := NewDedupRunner()
+
+	var calls int32
+	release := make(chan struct{})
+
+	first := d.Do("key", blockingCounter(&calls, release), 1)
+	d.Forget("key")
+	second := d.Do("key", blockingCounter(&calls, release), 2)
+	third := d.Do("key", blockingCounter(&calls, release), 3)
+
+	Expect(second.Shared()).To(BeFalse())
+	Expect(third.Shared()).To(BeTrue())
+	Expect(second.Task()).ToNot(BeIdenticalTo(first.Task()))
+
+	close(release)
+
+	res, _ := first.Wait(0)
+	Expect(res).To(Equal(NewValueResult(1, nil)))
+	res, _ = third.Wait(0)
+	Expect(res).To(Equal(NewValueResult(2, nil)))
+}
+
+func (s *DedupSuite) TestAbandon(t sweet.T) {
+	d := NewDedupRunner()
+
+	var calls int32
+	release := make(chan struct{})
+
+	first := d.Do("key", blockingCounter(&calls, release), 1)
+	second := d.Do("key", blockingCounter(&calls, release), 2)
+
+	first.Abandon()
+	first.Abandon()
+	Consistently(first.Task().Stopping()).ShouldNot(BeClosed())
+
+	second.Abandon()
+	Eventually(first.Task().Stopping()).Should(BeClosed())
+
+	res, err := first.Wait(0)
+	Expect(err).To(BeNil())
+	Expect(res.Err()).To(Equal(ErrCancelled))
+
+	third := d.Do("key", blockingCounter(&calls, release), 3)
+	Expect(third.Shared()).To(BeFalse())
+	close(release)
+
+	res, _ = third.Wait(0)
+	Expect(res).To(Equal(NewValueResult(3, nil)))
+}
+
+func (s *DedupSuite) TestContextAbandons(t sweet.T) {
+	d := NewDedupRunner()
+
+	var calls int32
+	release := make(chan struct{})
+	defer close(release)
+
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	ctx2, cancel2 := context.WithCancel(context.Background())
+
+	first := d.DoWithContext(ctx1, "key", blockingCounter(&calls, release), 1)
+	d.DoWithContext(ctx2, "key", blockingCounter(&calls, release), 2)
+
+	cancel1()
+	Consistently(first.Task().Stopping()).ShouldNot(BeClosed())
+
+	cancel2()
+	Eventually(first.Task().Stopping()).Should(BeClosed())
+}
+
+func (s *DedupSuite) TestWaitTimeout(t sweet.T) {
+	clock := glock.NewMockClock()
+	d := NewDedupRunner(WithClock(clock))
+
+	var calls int32
+	release := make(chan struct{})
+	defer close(release)
+
+	handle := d.Do("key", blockingCounter(&calls, release), 1)
+
+	go clock.BlockingAdvance(time.Second)
+	res, err := handle.Wait(time.Second)
+	Expect(err).To(Equal(ErrTimeout))
+	Expect(res).To(BeNil())
+}
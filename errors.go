@@ -2,6 +2,9 @@ package boom
 
 import (
 	"errors"
+	"fmt"
+	"sort"
+	"strings"
 )
 
 var (
@@ -17,4 +20,170 @@ var (
 
 	// ErrFinished is returned when execution for tasks has already finished
 	ErrFinished = errors.New("Execution has already finished")
+
+	// ErrPanic is returned by a PanicResult when a task's function panicked
+	ErrPanic = errors.New("Task panicked while executing")
+
+	// ErrQuorumUnreachable is returned when too many tasks have failed for
+	// a quorum to be reached
+	ErrQuorumUnreachable = errors.New("Quorum can no longer be reached")
+
+	// ErrResultType is returned when a task result doesn't hold a value of
+	// the expected type
+	ErrResultType = errors.New("Task result has an unexpected type")
+
+	// ErrDeadlineExceeded is returned when a task doesn't finish before the
+	// timeout set with WithTaskTimeout
+	ErrDeadlineExceeded = errors.New("Task deadline exceeded")
+
+	// ErrCancelled is returned when a task is cancelled before it started executing
+	ErrCancelled = errors.New("Task was cancelled before it started")
+
+	// ErrDuplicateNode is returned when a node is added to a DAG with a name
+	// that's already in use
+	ErrDuplicateNode = errors.New("A node with the name already exists")
+
+	// ErrQueueFull is returned when a task is submitted to a runner whose
+	// queue is full
+	ErrQueueFull = errors.New("Task queue is full")
+
+	// ErrDropped is returned when a queued task is dropped to make room for
+	// another task
+	ErrDropped = errors.New("Task was dropped from the queue")
+
+	// ErrRunnerClosed is returned when a task is submitted to a runner that
+	// has been closed
+	ErrRunnerClosed = errors.New("Runner has been closed")
+
+	// ErrDuplicateChild is returned when a child is added to a Supervisor with
+	// a name that's already in use
+	ErrDuplicateChild = errors.New("A child with the name already exists")
+
+	// ErrRestartIntensity is returned by a Supervisor when its children are
+	// restarted more often than its policy allows
+	ErrRestartIntensity = errors.New("Supervisor restart intensity exceeded")
+
+	// ErrCircuitOpen is returned when a call is made through a CircuitBreaker
+	// whose circuit is open
+	ErrCircuitOpen = errors.New("Circuit breaker is open")
+
+	// ErrNoTasks is returned when waiting on a RaceCollector that has no tasks
+	// to pick a winner from
+	ErrNoTasks = errors.New("No tasks were run")
+
+	// ErrNoResult is returned when none of a RaceCollector's tasks returned a
+	// successful result or an error
+	ErrNoResult = errors.New("No task returned a result")
 )
+
+// TimeoutError is returned when a collector times out waiting for results
+// and lists the indices of the tasks that had not finished.
+type TimeoutError struct {
+	Outstanding []int
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%s waiting for tasks %v", ErrTimeout, e.Outstanding)
+}
+
+// Timeout returns true, marking the error as a timeout for IsTimeout
+func (e *TimeoutError) Timeout() bool {
+	return true
+}
+
+// Unwrap returns ErrTimeout so errors.Is(err, ErrTimeout) matches a *TimeoutError
+func (e *TimeoutError) Unwrap() error {
+	return ErrTimeout
+}
+
+// IsTimeout returns true if err is ErrTimeout or an error reporting a timeout,
+// such as the *TimeoutError returned by AsyncCollector.WaitPartial.
+func IsTimeout(err error) bool {
+	if err == ErrTimeout {
+		return true
+	}
+
+	timeoutErr, ok := err.(interface {
+		Timeout() bool
+	})
+	return ok && timeoutErr.Timeout()
+}
+
+// TaskErrors is returned when one or more tasks in a collector failed and holds
+// the error from each failed task keyed by the task's index.
+type TaskErrors map[int]error
+
+func (e TaskErrors) Error() string {
+	indices := make([]int, 0, len(e))
+	for idx := range e {
+		indices = append(indices, idx)
+	}
+	sort.Ints(indices)
+
+	msgs := make([]string, 0, len(indices))
+	for _, idx := range indices {
+		msgs = append(msgs, fmt.Sprintf("task %d: %s", idx, e[idx]))
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// MissingDependencyError is returned when a node in a DAG depends on a node
+// that doesn't exist.
+type MissingDependencyError struct {
+	Node       string
+	Dependency string
+}
+
+func (e *MissingDependencyError) Error() string {
+	return fmt.Sprintf("Node %q depends on missing node %q", e.Node, e.Dependency)
+}
+
+// CycleError is returned when the nodes in a DAG depend on each other in a
+// cycle. Path lists the nodes in the cycle, starting and ending with the same node.
+type CycleError struct {
+	Path []string
+}
+
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("Dependency cycle detected: %s", strings.Join(e.Path, " -> "))
+}
+
+// UpstreamError is the error in the result of a DAG node that wasn't executed
+// because one of its dependencies failed.
+type UpstreamError struct {
+	Dependency string
+	Err        error
+}
+
+func (e *UpstreamError) Error() string {
+	return fmt.Sprintf("Dependency %q failed: %s", e.Dependency, e.Err)
+}
+
+// ShutdownError is returned when a runner is shut down before all of its
+// tasks have finished, or when a Supervisor's children don't finish within its
+// ShutdownTimeout, and lists the tasks that didn't finish.
+type ShutdownError struct {
+	Tasks []*Task
+}
+
+func (e *ShutdownError) Error() string {
+	tasks := make([]string, 0, len(e.Tasks))
+	for _, task := range e.Tasks {
+		if task.Name() == "" {
+			tasks = append(tasks, fmt.Sprintf("task %d", task.ID()))
+		} else {
+			tasks = append(tasks, fmt.Sprintf("task %d (%s)", task.ID(), task.Name()))
+		}
+	}
+	return fmt.Sprintf("%d tasks did not finish before shutdown: %s", len(e.Tasks), strings.Join(tasks, ", "))
+}
+
+// CronSyntaxError is returned when a cron expression can't be parsed
+type CronSyntaxError struct {
+	Expr   string
+	Reason string
+}
+
+func (e *CronSyntaxError) Error() string {
+	return fmt.Sprintf("Invalid cron expression %q: %s", e.Expr, e.Reason)
+}
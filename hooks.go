@@ -0,0 +1,70 @@
+package boom
+
+import (
+	"time"
+)
+
+// Hooks receives events from tasks as they move through their lifecycle, which
+// can be used to add logging, metrics or tracing to tasks. Hooks are called
+// synchronously so they should return quickly. Embed NopHooks to implement
+// only some of the methods.
+type Hooks interface {
+	// OnCreate is called when a task is created
+	OnCreate(task *Task)
+	// OnStart is called when a task is started
+	OnStart(task *Task)
+	// OnRunning is called when a task is set to the 'Running' state
+	OnRunning(task *Task)
+	// OnStop is called the first time a task is asked to stop
+	OnStop(task *Task)
+	// OnFinish is called when a task finishes with its result and how long
+	// it executed for. Tasks that finish without ever starting, such as ones
+	// dropped from a queue or run after a runner is closed, have a duration
+	// of 0 and their Started channel isn't closed.
+	OnFinish(task *Task, result TaskResult, duration time.Duration)
+	// OnDiscard is called when a task's result is discarded
+	OnDiscard(task *Task)
+	// OnTimeout is called when a task exceeds the timeout set with
+	// WithTaskTimeout or when waiting for a task times out, either
+	// directly or through a collector
+	OnTimeout(task *Task)
+}
+
+// CollectorHooks can be implemented by Hooks to also receive events from collectors.
+type CollectorHooks interface {
+	// OnCollectorTimeout is called when a collector times out waiting for
+	// results, with the number of tasks that were still outstanding
+	OnCollectorTimeout(outstanding int)
+}
+
+// NopHooks implements Hooks with methods that do nothing.
+type NopHooks struct{}
+
+func (NopHooks) OnCreate(task *Task)                                            {}
+func (NopHooks) OnStart(task *Task)                                             {}
+func (NopHooks) OnRunning(task *Task)                                           {}
+func (NopHooks) OnStop(task *Task)                                              {}
+func (NopHooks) OnFinish(task *Task, result TaskResult, duration time.Duration) {}
+func (NopHooks) OnDiscard(task *Task)                                           {}
+func (NopHooks) OnTimeout(task *Task)                                           {}
+
+// WithHooks adds hooks that are called as tasks move through their lifecycle.
+func WithHooks(hooks ...Hooks) TaskConfig {
+	return func(cfg *taskConfig) {
+		cfg.hooks = append(cfg.hooks, hooks...)
+	}
+}
+
+func (tc *taskConfig) eachHook(f func(Hooks)) {
+	for _, hooks := range tc.hooks {
+		f(hooks)
+	}
+}
+
+func (tc *taskConfig) collectorTimeout(outstanding int) {
+	for _, hooks := range tc.hooks {
+		if colHooks, ok := hooks.(CollectorHooks); ok {
+			colHooks.OnCollectorTimeout(outstanding)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package boom
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aphistic/sweet"
+	"github.com/efritz/glock"
+	. "github.com/onsi/gomega"
+)
+
+type HooksSuite struct{}
+
+type recordingHooks struct {
+	lock   sync.Mutex
+	events []string
+}
+
+func (h *recordingHooks) record(format string, args ...interface{}) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.events = append(h.events, fmt.Sprintf(format, args...))
+}
+
+func (h *recordingHooks) Events() []string {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return append([]string{}, h.events...)
+}
+
+func (h *recordingHooks) OnCreate(task *Task)  { h.record("create %s", task.Name()) }
+func (h *recordingHooks) OnStart(task *Task)   { h.record("start %s", task.Name()) }
+func (h *recordingHooks) OnRunning(task *Task) { h.record("running %s", task.Name()) }
+func (h *recordingHooks) OnStop(task *Task)    { h.record("stop %s", task.Name()) }
+func (h *recordingHooks) OnFinish(task *Task, result TaskResult, duration time.Duration) {
+	h.record("finish %s %v %s", task.Name(), result, duration)
+}
+func (h *recordingHooks) OnDiscard(task *Task) { h.record("discard %s", task.Name()) }
+func (h *recordingHooks) OnTimeout(task *Task) { h.record("timeout %s", task.Name()) }
+func (h *recordingHooks) OnCollectorTimeout(outstanding int) {
+	h.record("collector timeout %d", outstanding)
+}
+
+func (s *HooksSuite) TestTaskLifecycle(t sweet.T) {
+	clock := glock.NewMockClock()
+	hooks := &recordingHooks{}
+
+	tr := NewTaskRunner(WithClock(clock), WithHooks(hooks), WithName("task"))
+	task := tr.Run(func(task *Task, args ...interface{}) TaskResult {
+		task.SetRunning(true)
+		task.SetRunning(true)
+		<-task.Stopping()
+		clock.Advance(time.Second)
+		return NewValueResult(1, nil)
+	})
+
+	Expect(task.WaitForRunning(0)).To(BeNil())
+	task.Stop()
+	task.Stop()
+
+	_, err := task.Wait(0)
+	Expect(err).To(BeNil())
+
+	Expect(hooks.Events()).To(Equal([]string{
+		"create task",
+		"start task",
+		"running task",
+		"stop task",
+		"finish task &{1 <nil>} 1s",
+	}))
+}
+
+func (s *HooksSuite) TestTaskDiscardAndTimeout(t sweet.T) {
+	hooks := &recordingHooks{}
+
+	tr := NewTaskRunner(WithHooks(hooks), WithName("task"))
+	task := tr.Run(func(task *Task, args ...interface{}) TaskResult {
+		<-task.Stopping()
+		return nil
+	})
+
+	_, err := task.Wait(time.Millisecond)
+	Expect(err).To(Equal(ErrTimeout))
+	task.Discard()
+	task.Stop()
+
+	Eventually(hooks.Events).Should(ContainElement(HavePrefix("finish task <nil>")))
+	Expect(hooks.Events()).To(ContainElement("timeout task"))
+	Expect(hooks.Events()).To(ContainElement("discard task"))
+}
+
+func (s *HooksSuite) TestSkippedTask(t sweet.T) {
+	hooks := &recordingHooks{}
+
+	tr := NewTaskRunner(WithHooks(hooks), WithName("task"))
+	Expect(tr.Shutdown(context.Background())).To(BeNil())
+
+	task := tr.Run(func(task *Task, args ...interface{}) TaskResult {
+		return nil
+	})
+	_, err := task.Wait(0)
+	Expect(err).To(BeNil())
+
+	Expect(hooks.Events()).To(Equal([]string{
+		"create task",
+		"finish task &{Runner has been closed} 0s",
+	}))
+}
+
+func (s *HooksSuite) TestMultipleHooks(t sweet.T) {
+	first := &recordingHooks{}
+	second := &recordingHooks{}
+
+	tr := NewTaskRunner(WithHooks(first), WithName("task"))
+	tr.NewWithConfig(context.Background(), []TaskConfig{WithHooks(second)}, func(task *Task, args ...interface{}) TaskResult {
+		return nil
+	})
+	tr.New(func(task *Task, args ...interface{}) TaskResult {
+		return nil
+	})
+
+	Expect(first.Events()).To(Equal([]string{"create task", "create task"}))
+	Expect(second.Events()).To(Equal([]string{"create task"}))
+}
+
+func (s *HooksSuite) TestCollectorTimeout(t sweet.T) {
+	clock := glock.NewMockClock()
+	hooks := &recordingHooks{}
+
+	col := NewAsyncCollector(WithClock(clock), WithHooks(hooks))
+	col.RunWithConfig(context.Background(), []TaskConfig{WithName("done")}, func(task *Task, args ...interface{}) TaskResult {
+		return nil
+	})
+	col.RunWithConfig(context.Background(), []TaskConfig{WithName("slow")}, func(task *Task, args ...interface{}) TaskResult {
+		<-task.Stopping()
+		return nil
+	})
+
+	Eventually(func() TaskState { return col.tasks[0].task.State() }).Should(Equal(TaskFinished))
+
+	go clock.BlockingAdvance(time.Second)
+	_, err := col.WaitCloser(time.Second, func(res TaskResult) {})
+	Expect(err).To(Equal(ErrTimeout))
+
+	Expect(hooks.Events()).To(ContainElement("collector timeout 1"))
+	Expect(hooks.Events()).To(ContainElement("timeout slow"))
+	Expect(hooks.Events()).ToNot(ContainElement("timeout done"))
+
+	col.tasks[1].task.Stop()
+}
+
+func (s *HooksSuite) TestNopHooks(t sweet.T) {
+	var hooks Hooks = NopHooks{}
+	hooks.OnCreate(nil)
+	hooks.OnFinish(nil, nil, 0)
+}
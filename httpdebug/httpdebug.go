@@ -0,0 +1,222 @@
+// Package httpdebug provides an http.Handler that lists live boom tasks and
+// allows them to be stopped, similar to net/http/pprof.
+//
+// Mount the handler wherever it's convenient:
+//
+//	http.Handle("/debug/boom", httpdebug.New(runner))
+//
+// A GET request renders the tasks as HTML, or as JSON if the request has
+// a "format=json" query parameter or accepts application/json. A POST request
+// with a JSON body such as {"id": 1} stops the task with that ID. Requiring
+// a JSON body keeps other sites from stopping tasks with a cross-site form post.
+//
+// The handler doesn't authenticate requests, so anyone who can reach it can
+// read the stacks of tasks and stop them. It must not be exposed publicly.
+package httpdebug
+
+import (
+	"encoding/json"
+	"html/template"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aphistic/boom"
+	"github.com/efritz/glock"
+)
+
+// TaskLister lists tasks, such as a boom.TaskRunner or boom.AsyncCollector
+type TaskLister interface {
+	Tasks() []*boom.Task
+}
+
+// Config is the signature for functions that configure a Handler
+type Config func(*Handler)
+
+// Handler lists the tasks from a TaskLister over HTTP
+type Handler struct {
+	lister TaskLister
+	clock  glock.Clock
+}
+
+// TaskInfo is the information about a task rendered by a Handler
+type TaskInfo struct {
+	ID         uint64            `json:"id"`
+	Name       string            `json:"name"`
+	State      string            `json:"state"`
+	Created    time.Time         `json:"created"`
+	AgeSeconds float64           `json:"age_seconds"`
+	Labels     map[string]string `json:"labels"`
+	Stack      string            `json:"stack,omitempty"`
+
+	age time.Duration
+}
+
+// Age returns how long ago the task was created
+func (i TaskInfo) Age() time.Duration {
+	return i.age
+}
+
+// New creates a Handler listing the tasks from lister. It calls
+// boom.EnableTaskStacks so the stacks of tasks started afterwards are shown.
+func New(lister TaskLister, configs ...Config) *Handler {
+	h := &Handler{
+		lister: lister,
+		clock:  glock.NewRealClock(),
+	}
+
+	for _, f := range configs {
+		f(h)
+	}
+
+	boom.EnableTaskStacks()
+
+	return h
+}
+
+// WithClock sets the clock used to calculate the age of tasks. It should be
+// the same clock given to the tasks.
+func WithClock(clock glock.Clock) Config {
+	return func(h *Handler) {
+		h.clock = clock
+	}
+}
+
+// Tasks returns information about the tasks from the handler's TaskLister
+func (h *Handler) Tasks() []TaskInfo {
+	now := h.clock.Now()
+
+	tasks := h.lister.Tasks()
+	stacks := boom.TaskStacks(tasks)
+	infos := make([]TaskInfo, 0, len(tasks))
+	for _, task := range tasks {
+		snapshot := task.Snapshot()
+		created := snapshot.Times[boom.TaskCreated]
+
+		info := TaskInfo{
+			ID:      snapshot.ID,
+			Name:    snapshot.Name,
+			State:   snapshot.State.String(),
+			Created: created,
+			Labels:  snapshot.Labels,
+			Stack:   string(stacks[snapshot.ID]),
+			age:     now.Sub(created),
+		}
+		info.AgeSeconds = info.age.Seconds()
+
+		infos = append(infos, info)
+	}
+
+	return infos
+}
+
+// ServeHTTP implements http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		if wantsJSON(r) {
+			h.serveJSON(w)
+		} else {
+			h.serveHTML(w)
+		}
+	case http.MethodPost:
+		h.serveStop(w, r)
+	default:
+		w.Header().Set("Allow", "GET, HEAD, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func wantsJSON(r *http.Request) bool {
+	return r.URL.Query().Get("format") == "json" ||
+		strings.Contains(r.Header.Get("Accept"), "application/json")
+}
+
+func (h *Handler) serveJSON(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.Tasks())
+}
+
+func (h *Handler) serveHTML(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := tasksTemplate.Execute(w, h.Tasks()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) serveStop(w http.ResponseWriter, r *http.Request) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "application/json" {
+		http.Error(w, "content type must be application/json", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	var req struct {
+		ID uint64 `json:"id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid task id", http.StatusBadRequest)
+		return
+	}
+	id := req.ID
+
+	var task *boom.Task
+	for _, t := range h.lister.Tasks() {
+		if t.ID() == id {
+			task = t
+			break
+		}
+	}
+
+	if task == nil {
+		http.Error(w, "task not found", http.StatusNotFound)
+		return
+	}
+
+	if err := task.Stop(); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]uint64{"stopped": id})
+}
+
+var tasksTemplate = template.Must(template.New("tasks").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<title>boom tasks</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; vertical-align: top; }
+pre { margin: 0; }
+</style>
+<script>
+function stopTask(button) {
+	fetch(location.pathname, {
+		method: "POST",
+		headers: {"Content-Type": "application/json"},
+		body: JSON.stringify({id: Number(button.dataset.id)})
+	}).then(function() { location.reload(); });
+}
+</script>
+</head>
+<body>
+<h1>Tasks ({{len .}})</h1>
+<table>
+<tr><th>ID</th><th>Name</th><th>State</th><th>Age</th><th>Labels</th><th>Stack</th><th></th></tr>
+{{range .}}<tr>
+<td>{{.ID}}</td>
+<td>{{.Name}}</td>
+<td>{{.State}}</td>
+<td>{{.Age}}</td>
+<td>{{range $key, $value := .Labels}}{{$key}}={{$value}}<br>{{end}}</td>
+<td>{{if .Stack}}<details><summary>stack</summary><pre>{{.Stack}}</pre></details>{{end}}</td>
+<td>{{if ne .State "finished"}}<button data-id="{{.ID}}" onclick="stopTask(this)">Stop</button>{{end}}</td>
+</tr>
+{{end}}</table>
+</body>
+</html>
+`))
@@ -0,0 +1,200 @@
+package httpdebug
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aphistic/boom"
+	"github.com/aphistic/sweet"
+	junit "github.com/aphistic/sweet-junit"
+	"github.com/efritz/glock"
+	. "github.com/onsi/gomega"
+)
+
+func TestMain(m *testing.M) {
+	RegisterFailHandler(sweet.GomegaFail)
+
+	sweet.Run(m, func(s *sweet.S) {
+		s.RegisterPlugin(junit.NewPlugin())
+
+		s.AddSuite(&HandlerSuite{})
+	})
+}
+
+type HandlerSuite struct{}
+
+func blockingTask(task *boom.Task, args ...interface{}) boom.TaskResult {
+	task.SetRunning(true)
+	<-task.Stopping()
+	return nil
+}
+
+func get(server *httptest.Server, query string) (*http.Response, string) {
+	resp, err := http.Get(server.URL + query)
+	Expect(err).To(BeNil())
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	Expect(err).To(BeNil())
+	return resp, string(body)
+}
+
+func (s *HandlerSuite) TestTasks(t sweet.T) {
+	clock := glock.NewMockClock()
+	tr := boom.NewTaskRunner(
+		boom.WithClock(clock),
+		boom.WithName("worker"),
+		boom.WithLabels(map[string]string{"shard": "1"}),
+	)
+
+	h := New(tr, WithClock(clock))
+
+	task := tr.Run(blockingTask)
+	defer task.StopAndWait(0)
+	Expect(task.WaitForRunning(0)).To(BeNil())
+
+	clock.Advance(time.Minute)
+
+	infos := h.Tasks()
+	Expect(infos).To(HaveLen(1))
+	Expect(infos[0].ID).To(Equal(task.ID()))
+	Expect(infos[0].Name).To(Equal("worker"))
+	Expect(infos[0].State).To(Equal("running"))
+	Expect(infos[0].Labels).To(Equal(map[string]string{"shard": "1"}))
+	Expect(infos[0].Age()).To(Equal(time.Minute))
+	Expect(infos[0].AgeSeconds).To(Equal(60.0))
+	Expect(infos[0].Stack).To(ContainSubstring("blockingTask"))
+}
+
+func (s *HandlerSuite) TestJSON(t sweet.T) {
+	tr := boom.NewTaskRunner(boom.WithName("worker"))
+	server := httptest.NewServer(New(tr))
+	defer server.Close()
+
+	task := tr.Run(blockingTask)
+	defer task.StopAndWait(0)
+	Expect(task.WaitForRunning(0)).To(BeNil())
+
+	resp, body := get(server, "?format=json")
+	Expect(resp.Header.Get("Content-Type")).To(Equal("application/json"))
+
+	var infos []map[string]interface{}
+	Expect(json.Unmarshal([]byte(body), &infos)).To(BeNil())
+	Expect(infos).To(HaveLen(1))
+	Expect(infos[0]["id"]).To(Equal(float64(task.ID())))
+	Expect(infos[0]["name"]).To(Equal("worker"))
+	Expect(infos[0]["state"]).To(Equal("running"))
+	Expect(infos[0]).To(HaveKey("age_seconds"))
+	Expect(infos[0]["stack"]).To(ContainSubstring("blockingTask"))
+}
+
+func (s *HandlerSuite) TestHTML(t sweet.T) {
+	tr := boom.NewTaskRunner(boom.WithName("<worker>"))
+	server := httptest.NewServer(New(tr))
+	defer server.Close()
+
+	task := tr.Run(blockingTask)
+	defer task.StopAndWait(0)
+	Expect(task.WaitForRunning(0)).To(BeNil())
+
+	resp, body := get(server, "")
+	Expect(resp.Header.Get("Content-Type")).To(HavePrefix("text/html"))
+	Expect(body).To(ContainSubstring("Tasks (1)"))
+	Expect(body).To(ContainSubstring("&lt;worker&gt;"))
+	Expect(body).To(ContainSubstring("blockingTask"))
+	Expect(body).To(ContainSubstring(`data-id="` + formatID(task) + `"`))
+}
+
+func (s *HandlerSuite) TestCollectorTasks(t sweet.T) {
+	c := boom.NewAsyncCollector(boom.WithName("collected"))
+	c.Run(blockingTask)
+	c.Run(blockingTask)
+
+	infos := New(c).Tasks()
+	Expect(infos).To(HaveLen(2))
+	Expect(infos[0].Name).To(Equal("collected"))
+	Expect(infos[0].ID < infos[1].ID).To(BeTrue())
+
+	for _, task := range c.Tasks() {
+		Expect(task.WaitForRunning(0)).To(BeNil())
+		Expect(task.Stop()).To(BeNil())
+	}
+	_, err := c.Wait(0)
+	Expect(err).To(BeNil())
+}
+
+func (s *HandlerSuite) TestStop(t sweet.T) {
+	tr := boom.NewTaskRunner()
+	task := tr.Run(blockingTask)
+
+	server := httptest.NewServer(New(tr))
+	defer server.Close()
+
+	resp, err := http.Post(server.URL, "application/json", strings.NewReader(`{"id": `+formatID(task)+`}`))
+	Expect(err).To(BeNil())
+	defer resp.Body.Close()
+	Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+	var body map[string]uint64
+	Expect(json.NewDecoder(resp.Body).Decode(&body)).To(BeNil())
+	Expect(body).To(Equal(map[string]uint64{"stopped": task.ID()}))
+
+	Eventually(task.Finished()).Should(BeClosed())
+}
+
+func (s *HandlerSuite) TestStopRequiresJSON(t sweet.T) {
+	tr := boom.NewTaskRunner()
+	task := tr.Run(blockingTask)
+	defer task.StopAndWait(0)
+
+	server := httptest.NewServer(New(tr))
+	defer server.Close()
+
+	resp, err := http.PostForm(server.URL, url.Values{"id": {formatID(task)}})
+	Expect(err).To(BeNil())
+	resp.Body.Close()
+	Expect(resp.StatusCode).To(Equal(http.StatusUnsupportedMediaType))
+
+	Consistently(task.Stopping()).ShouldNot(BeClosed())
+}
+
+func (s *HandlerSuite) TestStopErrors(t sweet.T) {
+	tr := boom.NewTaskRunner()
+	task := tr.New(blockingTask)
+
+	server := httptest.NewServer(New(tr))
+	defer server.Close()
+
+	for id, status := range map[string]int{
+		`"abc"`:        http.StatusBadRequest,
+		"0":            http.StatusNotFound,
+		formatID(task): http.StatusConflict,
+	} {
+		resp, err := http.Post(server.URL, "application/json", strings.NewReader(`{"id": `+id+`}`))
+		Expect(err).To(BeNil())
+		resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(status))
+	}
+}
+
+func (s *HandlerSuite) TestMethodNotAllowed(t sweet.T) {
+	server := httptest.NewServer(New(boom.NewTaskRunner()))
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodDelete, server.URL, nil)
+	resp, err := http.DefaultClient.Do(req)
+	Expect(err).To(BeNil())
+	resp.Body.Close()
+	Expect(resp.StatusCode).To(Equal(http.StatusMethodNotAllowed))
+}
+
+func formatID(task *boom.Task) string {
+	return strconv.FormatUint(task.ID(), 10)
+}
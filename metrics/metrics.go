@@ -0,0 +1,246 @@
+// Package metrics records metrics for boom tasks and exposes them in the
+// Prometheus text exposition format.
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aphistic/boom"
+)
+
+// DefaultBuckets are the default upper bounds, in seconds, of the task duration histogram
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Config is the signature for functions that configure Metrics
+type Config func(*Metrics)
+
+// Metrics records metrics for the tasks it's configured on using boom's Hooks.
+// Metrics for each task are labeled with the task's name.
+type Metrics struct {
+	boom.NopHooks
+
+	namespace string
+	buckets   []float64
+
+	lock              sync.Mutex
+	tasks             map[string]*taskMetrics
+	collectorTimeouts uint64
+}
+
+type taskMetrics struct {
+	started  uint64
+	finished uint64
+	failed   uint64
+	panicked uint64
+	timeouts uint64
+	inFlight int64
+
+	durationCounts []uint64
+	durationSum    float64
+	durationCount  uint64
+}
+
+// New creates a new Metrics instance
+func New(configs ...Config) *Metrics {
+	m := &Metrics{
+		namespace: "boom",
+		buckets:   DefaultBuckets,
+		tasks:     make(map[string]*taskMetrics),
+	}
+
+	for _, f := range configs {
+		f(m)
+	}
+
+	return m
+}
+
+// WithNamespace sets the prefix of every metric name. The default is "boom".
+func WithNamespace(namespace string) Config {
+	return func(m *Metrics) {
+		m.namespace = namespace
+	}
+}
+
+// WithBuckets sets the upper bounds, in seconds, of the task duration histogram.
+func WithBuckets(buckets []float64) Config {
+	return func(m *Metrics) {
+		m.buckets = append([]float64{}, buckets...)
+		sort.Float64s(m.buckets)
+	}
+}
+
+// TaskConfig returns a boom.TaskConfig that records metrics for tasks
+// created by a runner or collector.
+func (m *Metrics) TaskConfig() boom.TaskConfig {
+	return boom.WithHooks(m)
+}
+
+// task returns the metrics for tasks with the given name. The lock must be held.
+func (m *Metrics) task(name string) *taskMetrics {
+	tm, ok := m.tasks[name]
+	if !ok {
+		tm = &taskMetrics{
+			durationCounts: make([]uint64, len(m.buckets)),
+		}
+		m.tasks[name] = tm
+	}
+	return tm
+}
+
+// OnStart implements boom.Hooks
+func (m *Metrics) OnStart(task *boom.Task) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	tm := m.task(task.Name())
+	tm.started++
+	tm.inFlight++
+}
+
+// OnFinish implements boom.Hooks
+func (m *Metrics) OnFinish(task *boom.Task, result boom.TaskResult, duration time.Duration) {
+	// Tasks that never started weren't counted by OnStart
+	select {
+	case <-task.Started():
+	default:
+		return
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	tm := m.task(task.Name())
+	tm.finished++
+	tm.inFlight--
+
+	if result != nil && result.Err() != nil {
+		tm.failed++
+	}
+	if _, ok := result.(*boom.PanicResult); ok {
+		tm.panicked++
+	}
+
+	seconds := duration.Seconds()
+	for idx, bound := range m.buckets {
+		if seconds <= bound {
+			tm.durationCounts[idx]++
+		}
+	}
+	tm.durationSum += seconds
+	tm.durationCount++
+}
+
+// OnTimeout implements boom.Hooks
+func (m *Metrics) OnTimeout(task *boom.Task) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.task(task.Name()).timeouts++
+}
+
+// OnCollectorTimeout implements boom.CollectorHooks
+func (m *Metrics) OnCollectorTimeout(outstanding int) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.collectorTimeouts++
+}
+
+// Handler returns an http.Handler that writes the metrics in the Prometheus
+// text exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		m.WriteTo(w)
+	})
+}
+
+// WriteTo writes the metrics to w in the Prometheus text exposition format.
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	names := make([]string, 0, len(m.tasks))
+	for name := range m.tasks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	buf := &bytes.Buffer{}
+
+	counters := []struct {
+		name  string
+		help  string
+		value func(*taskMetrics) uint64
+	}{
+		{"tasks_started_total", "Number of tasks started.", func(tm *taskMetrics) uint64 { return tm.started }},
+		{"tasks_finished_total", "Number of tasks finished.", func(tm *taskMetrics) uint64 { return tm.finished }},
+		{"tasks_failed_total", "Number of tasks finished with an error.", func(tm *taskMetrics) uint64 { return tm.failed }},
+		{"tasks_panicked_total", "Number of tasks that panicked.", func(tm *taskMetrics) uint64 { return tm.panicked }},
+		{"task_timeouts_total", "Number of times a task exceeded its deadline or waiting for it timed out.", func(tm *taskMetrics) uint64 { return tm.timeouts }},
+	}
+	for _, counter := range counters {
+		metric := m.metricName(counter.name)
+		writeHeader(buf, metric, counter.help, "counter")
+		for _, name := range names {
+			fmt.Fprintf(buf, "%s{name=%s} %d\n", metric, quote(name), counter.value(m.tasks[name]))
+		}
+	}
+
+	metric := m.metricName("tasks_in_flight")
+	writeHeader(buf, metric, "Number of tasks currently executing.", "gauge")
+	for _, name := range names {
+		fmt.Fprintf(buf, "%s{name=%s} %d\n", metric, quote(name), m.tasks[name].inFlight)
+	}
+
+	metric = m.metricName("task_duration_seconds")
+	writeHeader(buf, metric, "Time taken by tasks to execute.", "histogram")
+	for _, name := range names {
+		tm := m.tasks[name]
+		for idx, bound := range m.buckets {
+			fmt.Fprintf(buf, "%s_bucket{name=%s,le=\"%s\"} %d\n",
+				metric, quote(name), formatFloat(bound), tm.durationCounts[idx])
+		}
+		fmt.Fprintf(buf, "%s_bucket{name=%s,le=\"+Inf\"} %d\n", metric, quote(name), tm.durationCount)
+		fmt.Fprintf(buf, "%s_sum{name=%s} %s\n", metric, quote(name), formatFloat(tm.durationSum))
+		fmt.Fprintf(buf, "%s_count{name=%s} %d\n", metric, quote(name), tm.durationCount)
+	}
+
+	metric = m.metricName("collector_timeouts_total")
+	writeHeader(buf, metric, "Number of times a collector timed out waiting for results.", "counter")
+	fmt.Fprintf(buf, "%s %d\n", metric, m.collectorTimeouts)
+
+	return buf.WriteTo(w)
+}
+
+func (m *Metrics) metricName(name string) string {
+	if m.namespace == "" {
+		return name
+	}
+	return m.namespace + "_" + name
+}
+
+func writeHeader(buf *bytes.Buffer, metric string, help string, metricType string) {
+	fmt.Fprintf(buf, "# HELP %s %s\n", metric, help)
+	fmt.Fprintf(buf, "# TYPE %s %s\n", metric, metricType)
+}
+
+var labelReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// quote quotes and escapes a label value
+func quote(value string) string {
+	return `"` + labelReplacer.Replace(value) + `"`
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
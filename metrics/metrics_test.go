@@ -0,0 +1,158 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aphistic/boom"
+	"github.com/aphistic/sweet"
+	junit "github.com/aphistic/sweet-junit"
+	"github.com/efritz/glock"
+	. "github.com/onsi/gomega"
+)
+
+func TestMain(m *testing.M) {
+	RegisterFailHandler(sweet.GomegaFail)
+
+	sweet.Run(m, func(s *sweet.S) {
+		s.RegisterPlugin(junit.NewPlugin())
+
+		s.AddSuite(&MetricsSuite{})
+	})
+}
+
+type MetricsSuite struct{}
+
+func lines(m *Metrics) []string {
+	buf := &bytes.Buffer{}
+	m.WriteTo(buf)
+	return strings.Split(strings.TrimSpace(buf.String()), "\n")
+}
+
+func (s *MetricsSuite) TestTaskCounters(t sweet.T) {
+	m := New()
+	tr := boom.NewTaskRunner(
+		boom.WithClock(glock.NewMockClock()),
+		m.TaskConfig(),
+		boom.WithName("work"),
+	)
+
+	tr.Run(func(task *boom.Task, args ...interface{}) boom.TaskResult {
+		return boom.NewValueResult(1, nil)
+	}).Wait(0)
+	tr.Run(func(task *boom.Task, args ...interface{}) boom.TaskResult {
+		return boom.NewErrorResult(errors.New("failed"))
+	}).Wait(0)
+	tr.Run(func(task *boom.Task, args ...interface{}) boom.TaskResult {
+		panic("oops")
+	}).Wait(0)
+
+	Expect(lines(m)).To(ContainElement(`boom_tasks_started_total{name="work"} 3`))
+	Expect(lines(m)).To(ContainElement(`boom_tasks_finished_total{name="work"} 3`))
+	Expect(lines(m)).To(ContainElement(`boom_tasks_failed_total{name="work"} 2`))
+	Expect(lines(m)).To(ContainElement(`boom_tasks_panicked_total{name="work"} 1`))
+	Expect(lines(m)).To(ContainElement(`boom_tasks_in_flight{name="work"} 0`))
+	Expect(lines(m)).To(ContainElement(`boom_task_duration_seconds_count{name="work"} 3`))
+}
+
+func (s *MetricsSuite) TestInFlight(t sweet.T) {
+	m := New()
+	tr := boom.NewTaskRunner(m.TaskConfig())
+
+	task := tr.Run(func(task *boom.Task, args ...interface{}) boom.TaskResult {
+		<-task.Stopping()
+		return nil
+	})
+
+	Expect(lines(m)).To(ContainElement(`boom_tasks_in_flight{name=""} 1`))
+
+	task.StopAndWait(0)
+	Expect(lines(m)).To(ContainElement(`boom_tasks_in_flight{name=""} 0`))
+}
+
+func (s *MetricsSuite) TestSkippedTask(t sweet.T) {
+	m := New()
+	tr := boom.NewTaskRunner(m.TaskConfig())
+	tr.Shutdown(context.Background())
+
+	tr.Run(func(task *boom.Task, args ...interface{}) boom.TaskResult {
+		return nil
+	}).Wait(0)
+
+	Expect(lines(m)).ToNot(ContainElement(HavePrefix(`boom_tasks_finished_total{name=""}`)))
+	Expect(lines(m)).ToNot(ContainElement(HavePrefix(`boom_tasks_in_flight{name=""}`)))
+}
+
+func (s *MetricsSuite) TestDurationHistogram(t sweet.T) {
+	m := New(WithBuckets([]float64{1, 0.1}))
+	task := boom.NewTaskRunner(boom.WithName("hist")).Run(func(task *boom.Task, args ...interface{}) boom.TaskResult {
+		return nil
+	})
+	task.Wait(0)
+
+	m.OnStart(task)
+	m.OnFinish(task, nil, 50*time.Millisecond)
+	m.OnStart(task)
+	m.OnFinish(task, nil, 500*time.Millisecond)
+	m.OnStart(task)
+	m.OnFinish(task, nil, 2*time.Second)
+
+	Expect(lines(m)).To(ContainElement(`boom_task_duration_seconds_bucket{name="hist",le="0.1"} 1`))
+	Expect(lines(m)).To(ContainElement(`boom_task_duration_seconds_bucket{name="hist",le="1"} 2`))
+	Expect(lines(m)).To(ContainElement(`boom_task_duration_seconds_bucket{name="hist",le="+Inf"} 3`))
+	Expect(lines(m)).To(ContainElement(`boom_task_duration_seconds_sum{name="hist"} 2.55`))
+	Expect(lines(m)).To(ContainElement(`boom_task_duration_seconds_count{name="hist"} 3`))
+}
+
+func (s *MetricsSuite) TestCollectorTimeout(t sweet.T) {
+	m := New(WithNamespace("app"))
+	c := boom.NewAsyncCollector(m.TaskConfig(), boom.WithName("slow"))
+
+	c.Run(func(task *boom.Task, args ...interface{}) boom.TaskResult {
+		<-task.Stopping()
+		return nil
+	})
+
+	_, err := c.Wait(10 * time.Millisecond)
+	Expect(err).ToNot(BeNil())
+
+	Expect(lines(m)).To(ContainElement(`app_collector_timeouts_total 1`))
+	Expect(lines(m)).To(ContainElement(`app_task_timeouts_total{name="slow"} 1`))
+}
+
+func (s *MetricsSuite) TestLabelEscaping(t sweet.T) {
+	m := New()
+	task := boom.NewTaskRunner(boom.WithName("a \"b\"\n\\c")).New(nil)
+	m.OnStart(task)
+
+	Expect(lines(m)).To(ContainElement(`boom_tasks_started_total{name="a \"b\"\n\\c"} 1`))
+}
+
+func (s *MetricsSuite) TestHandler(t sweet.T) {
+	m := New()
+	tr := boom.NewTaskRunner(m.TaskConfig(), boom.WithName("http"))
+	tr.Run(func(task *boom.Task, args ...interface{}) boom.TaskResult {
+		return nil
+	}).Wait(0)
+
+	server := httptest.NewServer(m.Handler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	Expect(err).To(BeNil())
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	Expect(err).To(BeNil())
+	Expect(resp.Header.Get("Content-Type")).To(HavePrefix("text/plain; version=0.0.4"))
+	Expect(string(body)).To(ContainSubstring("# TYPE boom_tasks_started_total counter\n"))
+	Expect(string(body)).To(ContainSubstring(`boom_tasks_started_total{name="http"} 1`))
+	Expect(string(body)).To(ContainSubstring("# TYPE boom_task_duration_seconds histogram\n"))
+}
@@ -0,0 +1,29 @@
+package boom
+
+// FailPolicy is the signature for the function used by a fail-fast collector to
+// decide whether it should fail. It's called with the error from each task result
+// that has a non-nil error along with the number of failed results received so
+// far, including this one. Returning true causes the collector to fail with err.
+type FailPolicy func(err error, failures int) bool
+
+// FailOnFirstError returns a FailPolicy that fails on the first error received.
+func FailOnFirstError() FailPolicy {
+	return func(err error, failures int) bool {
+		return true
+	}
+}
+
+// FailOnErrorCount returns a FailPolicy that fails once n errors have been received.
+func FailOnErrorCount(n int) FailPolicy {
+	return func(err error, failures int) bool {
+		return failures >= n
+	}
+}
+
+// FailOnErrorMatch returns a FailPolicy that fails on the first error for which
+// match returns true. Errors that don't match are collected as normal results.
+func FailOnErrorMatch(match func(err error) bool) FailPolicy {
+	return func(err error, failures int) bool {
+		return match(err)
+	}
+}
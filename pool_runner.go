@@ -0,0 +1,146 @@
+package boom
+
+import (
+	"context"
+	"sync"
+)
+
+// OverflowPolicy determines what a PoolRunner does when a task is submitted
+// while its queue is full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock blocks the submitter until there is room in the queue
+	OverflowBlock OverflowPolicy = iota
+	// OverflowReject rejects the submitted task with ErrQueueFull
+	OverflowReject
+	// OverflowDropOldest removes the oldest task from the queue to make room
+	// for the submitted task. The dropped task finishes with ErrDropped. It
+	// requires a queue size greater than 0.
+	OverflowDropOldest
+)
+
+// PoolRunner executes tasks using a fixed number of long-lived workers. Tasks
+// are submitted to a bounded queue and started by the workers in the order
+// they were submitted.
+type PoolRunner struct {
+	cfg *taskConfig
+
+	lock   sync.RWMutex
+	closed bool
+	queue  chan *Task
+
+	workers sync.WaitGroup
+}
+
+// NewPoolRunner creates a new PoolRunner instance with the given number of workers
+// and a queue that holds up to queueSize tasks waiting for a worker. It panics if
+// queueSize is 0 with OverflowDropOldest, since there's no queued task to drop.
+func NewPoolRunner(workers int, queueSize int, configs ...TaskConfig) *PoolRunner {
+	cfg := newTaskConfig()
+	cfg.ApplyConfigs(configs)
+
+	if queueSize <= 0 && cfg.overflowPolicy == OverflowDropOldest {
+		panic("boom: OverflowDropOldest requires a queue")
+	}
+
+	pr := &PoolRunner{
+		cfg:   cfg,
+		queue: make(chan *Task, queueSize),
+	}
+
+	pr.workers.Add(workers)
+	for i := 0; i < workers; i++ {
+		go pr.worker()
+	}
+
+	return pr
+}
+
+func (pr *PoolRunner) worker() {
+	defer pr.workers.Done()
+
+	for task := range pr.queue {
+		if task.startLimited() == nil {
+			<-task.Finished()
+		}
+	}
+}
+
+// Submit creates a new task with the given function and arguments and adds it
+// to the queue to be started by a worker. The returned task can be waited on
+// or stopped before it has started. If the queue is full, the runner's
+// OverflowPolicy determines what happens.
+func (pr *PoolRunner) Submit(f TaskFunc, args ...interface{}) (*Task, error) {
+	return pr.SubmitWithContext(context.Background(), f, args...)
+}
+
+// SubmitWithContext calls Submit using the provided context.Context for the task.
+// If the runner is blocking on a full queue, it will stop waiting when ctx is done
+// and return ctx.Err(). Tasks that aren't queued finish with the returned error
+// without ever starting.
+func (pr *PoolRunner) SubmitWithContext(ctx context.Context, f TaskFunc, args ...interface{}) (*Task, error) {
+	pr.lock.RLock()
+	defer pr.lock.RUnlock()
+
+	if pr.closed {
+		return nil, ErrRunnerClosed
+	}
+
+	task := newTask(ctx, pr.cfg, f, args...)
+	task.schedule()
+
+	switch pr.cfg.overflowPolicy {
+	case OverflowReject:
+		select {
+		case pr.queue <- task:
+		default:
+			reject(task, ErrQueueFull)
+			return nil, ErrQueueFull
+		}
+	case OverflowDropOldest:
+		for {
+			select {
+			case pr.queue <- task:
+				return task, nil
+			default:
+			}
+
+			select {
+			case dropped := <-pr.queue:
+				dropped.skip(NewErrorResult(ErrDropped))
+			case pr.queue <- task:
+				return task, nil
+			}
+		}
+	default:
+		select {
+		case pr.queue <- task:
+		case <-ctx.Done():
+			reject(task, ctx.Err())
+			return nil, ctx.Err()
+		}
+	}
+
+	return task, nil
+}
+
+// reject skips a task that was never queued so it finishes with err
+func reject(task *Task, err error) {
+	task.skip(NewErrorResult(err))
+	// Receive the result so the task's goroutine can exit
+	task.Wait(0)
+}
+
+// Close stops the runner from accepting new tasks and waits for the workers
+// to finish the tasks already in the queue.
+func (pr *PoolRunner) Close() {
+	pr.lock.Lock()
+	if !pr.closed {
+		pr.closed = true
+		close(pr.queue)
+	}
+	pr.lock.Unlock()
+
+	pr.workers.Wait()
+}
@@ -0,0 +1,237 @@
+package boom
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aphistic/sweet"
+	. "github.com/onsi/gomega"
+)
+
+type PoolRunnerSuite struct{}
+
+func (s *PoolRunnerSuite) TestSubmit(t sweet.T) {
+	pr := NewPoolRunner(2, 10)
+	defer pr.Close()
+
+	var lock sync.Mutex
+	running := 0
+	maxRunning := 0
+	release := make(chan struct{})
+
+	tasks := []*Task{}
+	for i := 0; i < 6; i++ {
+		task, err := pr.Submit(func(task *Task, args ...interface{}) TaskResult {
+			lock.Lock()
+			running++
+			if running > maxRunning {
+				maxRunning = running
+			}
+			lock.Unlock()
+
+			<-release
+
+			lock.Lock()
+			running--
+			lock.Unlock()
+
+			return NewValueResult(args[0], nil)
+		}, i)
+		Expect(err).To(BeNil())
+		tasks = append(tasks, task)
+	}
+
+	Eventually(func() int {
+		lock.Lock()
+		defer lock.Unlock()
+		return running
+	}).Should(Equal(2))
+	close(release)
+
+	for i, task := range tasks {
+		res, err := task.Wait(time.Second)
+		Expect(err).To(BeNil())
+		Expect(res).To(Equal(NewValueResult(i, nil)))
+		Expect(task.Finished()).To(BeClosed())
+	}
+
+	Expect(maxRunning).To(Equal(2))
+}
+
+func (s *PoolRunnerSuite) TestStopQueued(t sweet.T) {
+	pr := NewPoolRunner(1, 1)
+	defer pr.Close()
+
+	blocker, err := pr.Submit(func(task *Task, args ...interface{}) TaskResult {
+		<-task.Stopping()
+		return NewValueResult(1, nil)
+	})
+	Expect(err).To(BeNil())
+	Eventually(blocker.Started()).Should(BeClosed())
+
+	queued, err := pr.Submit(func(task *Task, args ...interface{}) TaskResult {
+		return NewValueResult(2, nil)
+	})
+	Expect(err).To(BeNil())
+	Expect(queued.Started()).ToNot(BeClosed())
+
+	Expect(queued.Stop()).To(BeNil())
+	Expect(blocker.Stop()).To(BeNil())
+
+	res, err := queued.Wait(time.Second)
+	Expect(err).To(BeNil())
+	Expect(res).To(Equal(NewErrorResult(ErrCancelled)))
+	Expect(queued.Started()).ToNot(BeClosed())
+	Expect(queued.Finished()).To(BeClosed())
+}
+
+func (s *PoolRunnerSuite) TestOverflowReject(t sweet.T) {
+	pr := NewPoolRunner(1, 1, WithOverflowPolicy(OverflowReject))
+	defer pr.Close()
+
+	f := func(task *Task, args ...interface{}) TaskResult {
+		<-task.Stopping()
+		return nil
+	}
+
+	running, err := pr.Submit(f)
+	Expect(err).To(BeNil())
+	Eventually(running.Started()).Should(BeClosed())
+
+	queued, err := pr.Submit(f)
+	Expect(err).To(BeNil())
+
+	task, err := pr.Submit(f)
+	Expect(task).To(BeNil())
+	Expect(err).To(Equal(ErrQueueFull))
+
+	running.Stop()
+	queued.Stop()
+}
+
+func (s *PoolRunnerSuite) TestOverflowDropOldest(t sweet.T) {
+	pr := NewPoolRunner(1, 1, WithOverflowPolicy(OverflowDropOldest))
+	defer pr.Close()
+
+	release := make(chan struct{})
+	running, err := pr.Submit(func(task *Task, args ...interface{}) TaskResult {
+		<-release
+		return NewValueResult(1, nil)
+	})
+	Expect(err).To(BeNil())
+	Eventually(running.Started()).Should(BeClosed())
+
+	oldest, err := pr.Submit(func(task *Task, args ...interface{}) TaskResult {
+		return NewValueResult(2, nil)
+	})
+	Expect(err).To(BeNil())
+
+	newest, err := pr.Submit(func(task *Task, args ...interface{}) TaskResult {
+		return NewValueResult(3, nil)
+	})
+	Expect(err).To(BeNil())
+
+	res, err := oldest.Wait(time.Second)
+	Expect(err).To(BeNil())
+	Expect(res).To(Equal(NewErrorResult(ErrDropped)))
+
+	close(release)
+
+	res, err = newest.Wait(time.Second)
+	Expect(err).To(BeNil())
+	Expect(res).To(Equal(NewValueResult(3, nil)))
+}
+
+func (s *PoolRunnerSuite) TestOverflowBlockContext(t sweet.T) {
+	hooks := &createdHooks{created: make(chan *Task, 2)}
+	pr := NewPoolRunner(1, 0, WithHooks(hooks))
+	defer pr.Close()
+
+	running, err := pr.Submit(func(task *Task, args ...interface{}) TaskResult {
+		<-task.Stopping()
+		return nil
+	})
+	Expect(err).To(BeNil())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	task, err := pr.SubmitWithContext(ctx, func(task *Task, args ...interface{}) TaskResult {
+		return nil
+	})
+	Expect(task).To(BeNil())
+	Expect(err).To(Equal(context.DeadlineExceeded))
+	Expect(hooks.created).To(HaveLen(2))
+	<-hooks.created
+	Expect((<-hooks.created).Finished()).To(BeClosed())
+
+	running.Stop()
+}
+
+// createdHooks sends every task that's created on a channel
+type createdHooks struct {
+	NopHooks
+	created chan *Task
+}
+
+func (h *createdHooks) OnCreate(task *Task) { h.created <- task }
+
+func (s *PoolRunnerSuite) TestRejectedFinish(t sweet.T) {
+	hooks := &createdHooks{created: make(chan *Task, 3)}
+	pr := NewPoolRunner(1, 1, WithOverflowPolicy(OverflowReject), WithHooks(hooks))
+	defer pr.Close()
+
+	f := func(task *Task, args ...interface{}) TaskResult {
+		<-task.Stopping()
+		return nil
+	}
+
+	running, err := pr.Submit(f)
+	Expect(err).To(BeNil())
+	Eventually(running.Started()).Should(BeClosed())
+	queued, err := pr.Submit(f)
+	Expect(err).To(BeNil())
+
+	_, err = pr.Submit(f)
+	Expect(err).To(Equal(ErrQueueFull))
+
+	<-hooks.created
+	<-hooks.created
+	rejected := <-hooks.created
+	Expect(rejected.Finished()).To(BeClosed())
+	Expect(rejected.Started()).ToNot(BeClosed())
+	res, err := rejected.Wait(0)
+	Expect(err).To(BeNil())
+	Expect(res).To(Equal(NewErrorResult(ErrQueueFull)))
+
+	running.Stop()
+	queued.Stop()
+}
+
+func (s *PoolRunnerSuite) TestDropOldestNeedsQueue(t sweet.T) {
+	Expect(func() { NewPoolRunner(1, 0, WithOverflowPolicy(OverflowDropOldest)) }).To(Panic())
+}
+
+func (s *PoolRunnerSuite) TestClose(t sweet.T) {
+	pr := NewPoolRunner(1, 2)
+
+	release := make(chan struct{})
+	task, err := pr.Submit(func(task *Task, args ...interface{}) TaskResult {
+		<-release
+		return NewValueResult(1, nil)
+	})
+	Expect(err).To(BeNil())
+
+	Eventually(task.Started()).Should(BeClosed())
+	go close(release)
+
+	pr.Close()
+	Expect(task.Finished()).To(BeClosed())
+
+	task, err = pr.Submit(func(task *Task, args ...interface{}) TaskResult {
+		return nil
+	})
+	Expect(task).To(BeNil())
+	Expect(err).To(Equal(ErrRunnerClosed))
+}
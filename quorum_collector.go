@@ -0,0 +1,159 @@
+package boom
+
+import (
+	"context"
+	"time"
+)
+
+// QuorumCollector runs a number of tasks in parallel and waits until a quorum of
+// them have finished successfully. As soon as the quorum is met, or enough tasks
+// have failed that it can no longer be met, the remaining tasks are cancelled.
+type QuorumCollector struct {
+	col    *AsyncCollector
+	quorum int
+
+	decided bool
+	result  *QuorumResult
+	err     error
+}
+
+// QuorumResult describes the results that a QuorumCollector used to make its decision.
+type QuorumResult struct {
+	// Results contains the result of each task received before the decision was
+	// made, in the order the tasks were run. Tasks that were cancelled have a nil
+	// result.
+	Results []TaskResult
+	// Succeeded contains the indices of the tasks that finished without an error.
+	Succeeded []int
+	// Failed contains the indices of the tasks that finished with an error.
+	Failed []int
+}
+
+// NewQuorumCollector creates a new QuorumCollector instance that requires
+// quorum tasks to succeed.
+func NewQuorumCollector(quorum int, configs ...TaskConfig) *QuorumCollector {
+	return &QuorumCollector{
+		col:    NewAsyncCollector(configs...),
+		quorum: quorum,
+	}
+}
+
+// Run takes a TaskFunc to execute and zero or more parameters to pass to that
+// function and immediately starts executing the function. Functions run after
+// the quorum has been decided are never executed.
+func (c *QuorumCollector) Run(f TaskFunc, args ...interface{}) {
+	c.col.Run(f, args...)
+}
+
+// RunWithContext calls Run and uses the provided context.Context to run the task.
+func (c *QuorumCollector) RunWithContext(ctx context.Context, f TaskFunc, args ...interface{}) {
+	c.col.RunWithContext(ctx, f, args...)
+}
+
+// Wait calls WaitQuorum and returns the results the decision was made with.
+func (c *QuorumCollector) Wait(timeout time.Duration) ([]TaskResult, error) {
+	return c.WaitCloser(timeout, nil)
+}
+
+// WaitCloser calls WaitQuorumCloser and returns the results the decision was made with.
+func (c *QuorumCollector) WaitCloser(timeout time.Duration, closer CollectorCloser) ([]TaskResult, error) {
+	res, err := c.WaitQuorumCloser(timeout, closer)
+	if res == nil {
+		return nil, err
+	}
+	return res.Results, err
+}
+
+// WaitQuorum will wait until either the quorum of tasks has succeeded or enough
+// tasks have failed that the quorum can't be reached, in which case
+// ErrQuorumUnreachable is returned along with the results. Any tasks still
+// executing have their contexts cancelled. If no decision is made before the
+// timeout, the outstanding tasks are cancelled and ErrTimeout is returned, now
+// and on later calls. If timeout is 0, WaitQuorum will wait indefinitely for
+// the decision to be made.
+func (c *QuorumCollector) WaitQuorum(timeout time.Duration) (*QuorumResult, error) {
+	return c.WaitQuorumCloser(timeout, nil)
+}
+
+// WaitQuorumCloser will wait similar to WaitQuorum and call closer on the results
+// of tasks that finish after the quorum has been met. If the quorum can't be
+// reached or an error occurs while waiting, closer is called on the results of
+// all tasks, including the failed results the decision was made with.
+func (c *QuorumCollector) WaitQuorumCloser(timeout time.Duration, closer CollectorCloser) (*QuorumResult, error) {
+	col := c.col
+
+	col.lock.Lock()
+	defer col.lock.Unlock()
+
+	if c.decided {
+		return c.result, c.err
+	}
+
+	// The quorum can't be reached once more tasks have failed than are allowed to
+	allowedFailures := len(col.tasks) - c.quorum
+
+	res := &QuorumResult{}
+	decide := func() bool {
+		if len(res.Succeeded) >= c.quorum {
+			return true
+		}
+		if len(res.Failed) > allowedFailures {
+			c.err = ErrQuorumUnreachable
+			return true
+		}
+		return false
+	}
+
+	for idx, completed := range col.completed {
+		if completed {
+			c.record(res, idx, col.results[idx])
+		}
+	}
+
+	if !decide() {
+		err := col.collect(context.Background(), timeout, func(colRes *collectorResult) bool {
+			c.record(res, colRes.Choice, colRes.Result)
+			return decide()
+		})
+		if err == ErrTimeout {
+			c.decided = true
+			col.closed = true
+			c.err = ErrTimeout
+			col.abort(closer)
+			return nil, ErrTimeout
+		}
+
+		// Every result was received without a decision, so some tasks
+		// never ran and the quorum can't be met
+		if !decide() {
+			c.err = ErrQuorumUnreachable
+		}
+	}
+
+	c.decided = true
+	col.closed = true
+
+	res.Results = make([]TaskResult, len(col.results))
+	copy(res.Results, col.results)
+	c.result = res
+
+	// When the quorum is met, remove the results used for the decision so
+	// only tasks finishing after it are passed to closer.
+	if c.err == nil {
+		for idx := range col.results {
+			col.results[idx] = nil
+		}
+	}
+
+	col.abort(closer)
+
+	return c.result, c.err
+}
+
+func (c *QuorumCollector) record(res *QuorumResult, idx int, taskRes TaskResult) {
+	if taskRes != nil && taskRes.Err() != nil {
+		res.Failed = append(res.Failed, idx)
+	} else {
+		res.Succeeded = append(res.Succeeded, idx)
+	}
+}
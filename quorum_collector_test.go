@@ -0,0 +1,165 @@
+package boom
+
+import (
+	"errors"
+	"time"
+
+	"github.com/aphistic/sweet"
+	"github.com/efritz/glock"
+	. "github.com/onsi/gomega"
+)
+
+type QuorumColSuite struct{}
+
+func (s *QuorumColSuite) TestFitsCollector(t sweet.T) {
+	col := NewQuorumCollector(1)
+
+	func(c Collector) {
+
+	}(col)
+}
+
+func (s *QuorumColSuite) TestWaitQuorumMet(t sweet.T) {
+	col := NewQuorumCollector(2)
+
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		return NewValueResult(1, nil)
+	})
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		<-task.Stopping()
+		return NewValueResult(2, nil)
+	})
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		return NewValueResult(3, nil)
+	})
+
+	closed := make(chan TaskResult, 3)
+	res, err := col.WaitQuorumCloser(time.Second, func(res TaskResult) {
+		closed <- res
+	})
+	Expect(err).To(BeNil())
+	Expect(res.Succeeded).To(ConsistOf(0, 2))
+	Expect(res.Failed).To(BeEmpty())
+	Expect(res.Results).To(Equal([]TaskResult{
+		NewValueResult(1, nil),
+		nil,
+		NewValueResult(3, nil),
+	}))
+
+	Expect(col.col.tasks[1].task.Stopping()).To(BeClosed())
+	Eventually(closed).Should(Receive(Equal(NewValueResult(2, nil))))
+	Consistently(closed).ShouldNot(Receive())
+}
+
+func (s *QuorumColSuite) TestWaitQuorumUnreachable(t sweet.T) {
+	col := NewQuorumCollector(2)
+
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		return NewErrorResult(errors.New("first"))
+	})
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		return NewErrorResult(errors.New("second"))
+	})
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		<-task.Stopping()
+		return NewValueResult(3, nil)
+	})
+
+	res, err := col.WaitQuorum(time.Second)
+	Expect(err).To(Equal(ErrQuorumUnreachable))
+	Expect(res.Succeeded).To(BeEmpty())
+	Expect(res.Failed).To(ConsistOf(0, 1))
+
+	Expect(col.col.tasks[2].task.Stopping()).To(BeClosed())
+
+	results, err := col.Wait(time.Second)
+	Expect(err).To(Equal(ErrQuorumUnreachable))
+	Expect(results).To(HaveLen(3))
+}
+
+func (s *QuorumColSuite) TestWaitQuorumUnreachableCloser(t sweet.T) {
+	col := NewQuorumCollector(2)
+
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		return NewErrorResult(errors.New("first"))
+	})
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		return NewErrorResult(errors.New("second"))
+	})
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		<-task.Stopping()
+		return NewValueResult(3, nil)
+	})
+
+	closed := make(chan TaskResult, 3)
+	_, err := col.WaitQuorumCloser(time.Second, func(res TaskResult) {
+		closed <- res
+	})
+	Expect(err).To(Equal(ErrQuorumUnreachable))
+
+	Eventually(closed).Should(HaveLen(3))
+	Expect((<-closed).Err()).To(Equal(errors.New("first")))
+	Expect((<-closed).Err()).To(Equal(errors.New("second")))
+	Expect((<-closed).(*ValueResult).Value).To(Equal(3))
+}
+
+func (s *QuorumColSuite) TestWaitQuorumTooLarge(t sweet.T) {
+	col := NewQuorumCollector(2)
+
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		<-task.Stopping()
+		return NewValueResult(1, nil)
+	})
+
+	res, err := col.WaitQuorum(time.Second)
+	Expect(err).To(Equal(ErrQuorumUnreachable))
+	Expect(res.Succeeded).To(BeEmpty())
+	Expect(res.Failed).To(BeEmpty())
+}
+
+func (s *QuorumColSuite) TestWaitQuorumTimeout(t sweet.T) {
+	clock := glock.NewMockClock()
+
+	col := NewQuorumCollector(2, WithClock(clock), WithMaxConcurrency(1))
+
+	running := make(chan struct{})
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		close(running)
+		<-task.Stopping()
+		return NewValueResult(1, nil)
+	})
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		return NewValueResult(2, nil)
+	})
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		return NewValueResult(3, nil)
+	})
+
+	<-running
+
+	go clock.BlockingAdvance(10 * time.Millisecond)
+	res, err := col.WaitQuorum(10 * time.Millisecond)
+	Expect(res).To(BeNil())
+	Expect(err).To(Equal(ErrTimeout))
+	Expect(col.col.tasks[0].task.Stopping()).To(BeClosed())
+
+	res, err = col.WaitQuorum(0)
+	Expect(res).To(BeNil())
+	Expect(err).To(Equal(ErrTimeout))
+}
+
+func (s *QuorumColSuite) TestRunAfterDecided(t sweet.T) {
+	col := NewQuorumCollector(1)
+
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		return NewValueResult(1, nil)
+	})
+
+	_, err := col.WaitQuorum(time.Second)
+	Expect(err).To(BeNil())
+
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		return NewValueResult(2, nil)
+	})
+	Expect(col.col.Tasks()).To(HaveLen(1))
+}
@@ -0,0 +1,121 @@
+package boom
+
+import (
+	"context"
+	"time"
+)
+
+// RaceCollector runs a number of tasks in parallel and collects the result of the
+// first task to finish successfully. Once a task succeeds the remaining tasks are
+// cancelled. This is useful for hedging a request across several replicas.
+type RaceCollector struct {
+	col *AsyncCollector
+
+	decided bool
+	winner  TaskResult
+	err     error
+}
+
+// NewRaceCollector creates a new RaceCollector instance
+func NewRaceCollector(configs ...TaskConfig) *RaceCollector {
+	return &RaceCollector{
+		col: NewAsyncCollector(configs...),
+	}
+}
+
+// Run takes a TaskFunc to execute and zero or more parameters to pass to that
+// function and immediately starts executing the function. Functions run after
+// the race has been decided are never executed.
+func (c *RaceCollector) Run(f TaskFunc, args ...interface{}) {
+	c.col.Run(f, args...)
+}
+
+// RunWithContext calls Run and uses the provided context.Context to run the task.
+func (c *RaceCollector) RunWithContext(ctx context.Context, f TaskFunc, args ...interface{}) {
+	c.col.RunWithContext(ctx, f, args...)
+}
+
+// Wait calls WaitFirst and returns the winning result as the only element of
+// the returned slice.
+func (c *RaceCollector) Wait(timeout time.Duration) ([]TaskResult, error) {
+	return c.WaitCloser(timeout, nil)
+}
+
+// WaitCloser calls WaitFirstCloser and returns the winning result as the only
+// element of the returned slice.
+func (c *RaceCollector) WaitCloser(timeout time.Duration, closer CollectorCloser) ([]TaskResult, error) {
+	res, err := c.WaitFirstCloser(timeout, closer)
+	if err != nil {
+		return nil, err
+	}
+	return []TaskResult{res}, nil
+}
+
+// WaitFirst will wait until a task finishes with a result that doesn't have an
+// error and return that result, cancelling the contexts of the remaining tasks.
+// Tasks returning a nil result can't win. If every task fails, the error from
+// the first failed result is returned, or ErrNoResult if no task failed with an
+// error, and if no tasks were run ErrNoTasks is returned. If no task succeeds before the
+// timeout, the contexts of all outstanding tasks are cancelled and ErrTimeout
+// is returned. If timeout is 0, WaitFirst will wait indefinitely for a task to
+// succeed.
+func (c *RaceCollector) WaitFirst(timeout time.Duration) (TaskResult, error) {
+	return c.WaitFirstCloser(timeout, nil)
+}
+
+// WaitFirstCloser will wait similar to WaitFirst and call closer on the results
+// of every task other than the winner, including tasks that finish after being
+// cancelled. If an error occurs while waiting, closer is called on the results
+// of all tasks.
+func (c *RaceCollector) WaitFirstCloser(timeout time.Duration, closer CollectorCloser) (TaskResult, error) {
+	col := c.col
+
+	col.lock.Lock()
+	defer col.lock.Unlock()
+
+	if c.decided {
+		return c.winner, c.err
+	}
+
+	if len(col.tasks) == 0 {
+		return nil, ErrNoTasks
+	}
+
+	winner := -1
+	var firstErr error
+	err := col.collect(context.Background(), timeout, func(res *collectorResult) bool {
+		if res.Result == nil {
+			return false
+		}
+		if res.Result.Err() != nil {
+			if firstErr == nil {
+				firstErr = res.Result.Err()
+			}
+			return false
+		}
+
+		winner = res.Choice
+		return true
+	})
+
+	c.decided = true
+	col.closed = true
+
+	if err == ErrTimeout {
+		c.err = ErrTimeout
+	} else if winner < 0 {
+		c.err = firstErr
+		if c.err == nil {
+			c.err = ErrNoResult
+		}
+	} else {
+		// Remove the winner from the collector's results so it isn't
+		// passed to closer with the losers.
+		c.winner = col.results[winner]
+		col.results[winner] = nil
+	}
+
+	col.abort(closer)
+
+	return c.winner, c.err
+}
@@ -0,0 +1,239 @@
+package boom
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aphistic/sweet"
+	"github.com/efritz/glock"
+	. "github.com/onsi/gomega"
+)
+
+type RaceColSuite struct{}
+
+func (s *RaceColSuite) TestFitsCollector(t sweet.T) {
+	col := NewRaceCollector()
+
+	func(c Collector) {
+
+	}(col)
+}
+
+func (s *RaceColSuite) TestWaitFirst(t sweet.T) {
+	col := NewRaceCollector()
+
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		<-task.Stopping()
+		return NewValueResult(1, nil)
+	})
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		return NewValueResult(2, nil)
+	})
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		<-task.Stopping()
+		return NewValueResult(3, nil)
+	})
+
+	closed := make(chan TaskResult, 3)
+	res, err := col.WaitFirstCloser(time.Second, func(res TaskResult) {
+		closed <- res
+	})
+	Expect(err).To(BeNil())
+	Expect(res).To(Equal(NewValueResult(2, nil)))
+
+	var losers []TaskResult
+	for i := 0; i < 2; i++ {
+		var res TaskResult
+		Eventually(closed).Should(Receive(&res))
+		losers = append(losers, res)
+	}
+	Expect(losers).To(ConsistOf(NewValueResult(1, nil), NewValueResult(3, nil)))
+	Consistently(closed).ShouldNot(Receive())
+
+	Expect(col.col.tasks[1].task.Stopping()).ToNot(BeClosed())
+
+	res, err = col.WaitFirst(time.Second)
+	Expect(err).To(BeNil())
+	Expect(res).To(Equal(NewValueResult(2, nil)))
+}
+
+func (s *RaceColSuite) TestWaitFirstSkipsErrors(t sweet.T) {
+	col := NewRaceCollector()
+
+	failed := make(chan struct{})
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		defer close(failed)
+		return NewErrorResult(errors.New("I'm an error! - Ralph"))
+	})
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		<-failed
+		return NewValueResult(2, nil)
+	})
+
+	closed := make(chan TaskResult, 2)
+	res, err := col.WaitCloser(time.Second, func(res TaskResult) {
+		closed <- res
+	})
+	Expect(err).To(BeNil())
+	Expect(res).To(Equal([]TaskResult{NewValueResult(2, nil)}))
+	Eventually(closed).Should(Receive(Equal(NewErrorResult(errors.New("I'm an error! - Ralph")))))
+}
+
+func (s *RaceColSuite) TestWaitFirstAllFail(t sweet.T) {
+	col := NewRaceCollector()
+
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		return NewErrorResult(errors.New("first"))
+	})
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		return NewErrorResult(errors.New("second"))
+	})
+
+	// Either error can be received first
+	res, err := col.WaitFirst(time.Second)
+	Expect(res).To(BeNil())
+	Expect([]error{errors.New("first"), errors.New("second")}).To(ContainElement(Equal(err)))
+
+	// Later calls return the same error
+	res, again := col.WaitFirst(time.Second)
+	Expect(res).To(BeNil())
+	Expect(again).To(BeIdenticalTo(err))
+}
+
+func (s *RaceColSuite) TestWaitFirstTimeout(t sweet.T) {
+	clock := glock.NewMockClock()
+
+	col := NewRaceCollector(WithClock(clock))
+
+	var running sync.WaitGroup
+	running.Add(2)
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		running.Done()
+		clock.Sleep(20 * time.Millisecond)
+		return NewValueResult(1, nil)
+	})
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		running.Done()
+		clock.Sleep(20 * time.Millisecond)
+		return NewValueResult(2, nil)
+	})
+
+	running.Wait()
+
+	var closed sync.WaitGroup
+	closed.Add(2)
+	go clock.BlockingAdvance(10 * time.Millisecond)
+	res, err := col.WaitFirstCloser(10*time.Millisecond, func(res TaskResult) {
+		closed.Done()
+	})
+	Expect(res).To(BeNil())
+	Expect(err).To(Equal(ErrTimeout))
+
+	go clock.BlockingAdvance(10 * time.Millisecond)
+	closed.Wait()
+}
+
+func (s *RaceColSuite) TestWaitFirstTimeoutCancels(t sweet.T) {
+	clock := glock.NewMockClock()
+
+	col := NewRaceCollector(WithClock(clock))
+
+	var running sync.WaitGroup
+	running.Add(2)
+	for i := 0; i < 2; i++ {
+		col.Run(func(task *Task, data ...interface{}) TaskResult {
+			running.Done()
+			<-task.Stopping()
+			return NewErrorResult(task.Context().Err())
+		})
+	}
+
+	running.Wait()
+
+	closed := make(chan TaskResult, 2)
+	go clock.BlockingAdvance(10 * time.Millisecond)
+	res, err := col.WaitFirstCloser(10*time.Millisecond, func(res TaskResult) {
+		closed <- res
+	})
+	Expect(res).To(BeNil())
+	Expect(err).To(Equal(ErrTimeout))
+
+	Eventually(closed).Should(HaveLen(2))
+	Expect((<-closed).Err()).To(Equal(context.Canceled))
+	Expect((<-closed).Err()).To(Equal(context.Canceled))
+
+	res, err = col.WaitFirst(0)
+	Expect(res).To(BeNil())
+	Expect(err).To(Equal(ErrTimeout))
+}
+
+func (s *RaceColSuite) TestWaitNoTasks(t sweet.T) {
+	col := NewRaceCollector()
+
+	res, err := col.WaitFirst(time.Second)
+	Expect(res).To(BeNil())
+	Expect(err).To(Equal(ErrNoTasks))
+
+	results, err := col.Wait(time.Second)
+	Expect(results).To(BeNil())
+	Expect(err).To(Equal(ErrNoTasks))
+}
+
+func (s *RaceColSuite) TestWaitFirstSkipsNil(t sweet.T) {
+	col := NewRaceCollector()
+
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		return nil
+	})
+	second := make(chan struct{})
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		<-second
+		return NewValueResult(2, nil)
+	})
+
+	Eventually(col.col.tasks[0].task.Finished()).Should(BeClosed())
+	close(second)
+
+	res, err := col.WaitFirst(time.Second)
+	Expect(err).To(BeNil())
+	Expect(res).To(Equal(NewValueResult(2, nil)))
+}
+
+func (s *RaceColSuite) TestWaitFirstAllNil(t sweet.T) {
+	col := NewRaceCollector()
+
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		return nil
+	})
+
+	res, err := col.WaitFirst(time.Second)
+	Expect(res).To(BeNil())
+	Expect(err).To(Equal(ErrNoResult))
+}
+
+func (s *RaceColSuite) TestRunAfterDecided(t sweet.T) {
+	col := NewRaceCollector()
+
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		return NewValueResult(1, nil)
+	})
+
+	res, err := col.WaitFirst(time.Second)
+	Expect(err).To(BeNil())
+	Expect(res).To(Equal(NewValueResult(1, nil)))
+
+	var ran int32
+	col.Run(func(task *Task, data ...interface{}) TaskResult {
+		atomic.AddInt32(&ran, 1)
+		return NewValueResult(2, nil)
+	})
+	Expect(col.col.Tasks()).To(HaveLen(1))
+	Consistently(func() int32 { return atomic.LoadInt32(&ran) }).Should(BeZero())
+
+	res, err = col.WaitFirst(time.Second)
+	Expect(err).To(BeNil())
+	Expect(res).To(Equal(NewValueResult(1, nil)))
+}
@@ -0,0 +1,173 @@
+package boom
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimiter limits how often tasks start using a token bucket. Tokens are
+// added to the bucket at a fixed interval up to the bucket's size, and each
+// task takes a token when it starts. Tasks that find the bucket empty wait
+// until a token is added. Time is measured using the clock of the tasks the
+// RateLimiter is used with.
+type RateLimiter struct {
+	interval time.Duration
+	burst    int
+
+	lock sync.Mutex
+	// next is the time the bucket would next be empty if no tasks started
+	// before then, which is how the bucket's tokens are tracked
+	next  time.Time
+	stats RateLimiterStats
+}
+
+// RateLimiterStats holds statistics about the tasks a RateLimiter has limited.
+type RateLimiterStats struct {
+	// Tasks is the number of tasks that were allowed to start
+	Tasks uint64
+	// Delayed is the number of tasks that had to wait before starting
+	Delayed uint64
+	// Cancelled is the number of tasks that were stopped while waiting
+	Cancelled uint64
+	// TotalWait is the total time tasks that started waited
+	TotalWait time.Duration
+	// MaxWait is the longest time a task that started waited
+	MaxWait time.Duration
+}
+
+// AverageWait returns the average time tasks that started waited.
+func (s RateLimiterStats) AverageWait() time.Duration {
+	if s.Tasks == 0 {
+		return 0
+	}
+	return s.TotalWait / time.Duration(s.Tasks)
+}
+
+// NewTokenBucket creates a RateLimiter that allows a task to start every
+// interval on average, with up to burst tasks starting at once.
+func NewTokenBucket(interval time.Duration, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &RateLimiter{
+		interval: interval,
+		burst:    burst,
+	}
+}
+
+// NewLeakyBucket creates a RateLimiter that starts tasks at a steady rate of
+// one every interval, without allowing bursts.
+func NewLeakyBucket(interval time.Duration) *RateLimiter {
+	return NewTokenBucket(interval, 1)
+}
+
+// WithRateLimiter delays starting tasks until limiter allows them to start.
+// Tasks can be waited on or stopped while they're delayed, and a task stopped
+// before it starts finishes with an ErrCancelled result. The same RateLimiter
+// may be shared by several runners and collectors.
+func WithRateLimiter(limiter *RateLimiter) TaskConfig {
+	return func(cfg *taskConfig) {
+		cfg.rateLimiter = limiter
+	}
+}
+
+// Stats returns statistics about the tasks the RateLimiter has limited.
+func (l *RateLimiter) Stats() RateLimiterStats {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return l.stats
+}
+
+// reserve takes a token from the bucket and returns how long to wait until
+// the token is available.
+func (l *RateLimiter) reserve(now time.Time) time.Duration {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.next.Before(now) {
+		l.next = now
+	}
+
+	wait := l.next.Sub(now) - time.Duration(l.burst-1)*l.interval
+	l.next = l.next.Add(l.interval)
+
+	if wait < 0 {
+		return 0
+	}
+	return wait
+}
+
+// cancel records a task that was stopped while waiting, returning the token
+// it reserved if it had one.
+func (l *RateLimiter) cancel(reserved bool) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if reserved {
+		l.next = l.next.Add(-l.interval)
+	}
+	l.stats.Cancelled++
+}
+
+func (l *RateLimiter) record(wait time.Duration) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.stats.Tasks++
+	if wait > 0 {
+		l.stats.Delayed++
+	}
+	l.stats.TotalWait += wait
+	if wait > l.stats.MaxWait {
+		l.stats.MaxWait = wait
+	}
+}
+
+// wait waits until the task is allowed to start. It returns false if the task
+// is stopped while waiting.
+func (l *RateLimiter) wait(task *Task) bool {
+	select {
+	case <-task.Stopping():
+		l.cancel(false)
+		return false
+	default:
+	}
+
+	wait := l.reserve(task.cfg.clock.Now())
+	if wait > 0 {
+		select {
+		case <-task.cfg.clock.After(wait):
+		case <-task.Stopping():
+			l.cancel(true)
+			return false
+		}
+	}
+
+	l.record(wait)
+	return true
+}
+
+// startLimited starts the task once its rate limiter, if it has one, allows it
+// to. If the task is stopped first, it's skipped with an ErrCancelled result and
+// ErrCancelled is returned. Otherwise the error from Start is returned.
+func (t *Task) startLimited() error {
+	allowed := true
+	if t.cfg.rateLimiter != nil {
+		allowed = t.cfg.rateLimiter.wait(t)
+	} else {
+		select {
+		case <-t.Stopping():
+			allowed = false
+		default:
+		}
+	}
+
+	if !allowed {
+		t.skip(NewErrorResult(ErrCancelled))
+		return ErrCancelled
+	}
+
+	return t.Start()
+}
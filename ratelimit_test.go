@@ -0,0 +1,171 @@
+package boom
+
+import (
+	"context"
+	"time"
+
+	"github.com/aphistic/sweet"
+	"github.com/efritz/glock"
+	. "github.com/onsi/gomega"
+)
+
+type RateLimitSuite struct{}
+
+func (s *RateLimitSuite) TestReserve(t sweet.T) {
+	now := time.Unix(100, 0)
+	l := NewTokenBucket(time.Second, 3)
+
+	// The bucket starts full
+	Expect(l.reserve(now)).To(Equal(time.Duration(0)))
+	Expect(l.reserve(now)).To(Equal(time.Duration(0)))
+	Expect(l.reserve(now)).To(Equal(time.Duration(0)))
+	Expect(l.reserve(now)).To(Equal(time.Second))
+	Expect(l.reserve(now)).To(Equal(2 * time.Second))
+
+	l.cancel(true)
+	Expect(l.reserve(now)).To(Equal(2 * time.Second))
+
+	// Tokens are added over time, up to the size of the bucket
+	now = now.Add(3 * time.Second)
+	Expect(l.reserve(now)).To(Equal(time.Duration(0)))
+	Expect(l.reserve(now)).To(Equal(time.Second))
+
+	now = now.Add(time.Hour)
+	for i := 0; i < 3; i++ {
+		Expect(l.reserve(now)).To(Equal(time.Duration(0)))
+	}
+	Expect(l.reserve(now)).To(Equal(time.Second))
+}
+
+func (s *RateLimitSuite) TestLeakyBucket(t sweet.T) {
+	now := time.Unix(100, 0)
+	l := NewLeakyBucket(100 * time.Millisecond)
+
+	Expect(l.reserve(now)).To(Equal(time.Duration(0)))
+	Expect(l.reserve(now)).To(Equal(100 * time.Millisecond))
+	Expect(l.reserve(now)).To(Equal(200 * time.Millisecond))
+}
+
+func (s *RateLimitSuite) TestRunner(t sweet.T) {
+	clock := glock.NewMockClock()
+	limiter := NewLeakyBucket(time.Second)
+	tr := NewTaskRunner(WithClock(clock), WithRateLimiter(limiter))
+
+	f := func(task *Task, args ...interface{}) TaskResult {
+		return NewValueResult(args[0], nil)
+	}
+
+	first := tr.Run(f, 1)
+	res, err := first.Wait(0)
+	Expect(err).To(BeNil())
+	Expect(res).To(Equal(NewValueResult(1, nil)))
+
+	second := tr.Run(f, 2)
+	Consistently(second.Started()).ShouldNot(BeClosed())
+	Expect(tr.Tasks()).To(ConsistOf(BeIdenticalTo(second)))
+
+	clock.BlockingAdvance(time.Second)
+	res, err = second.Wait(0)
+	Expect(err).To(BeNil())
+	Expect(res).To(Equal(NewValueResult(2, nil)))
+	Expect(second.StateTime(TaskStarted)).To(Equal(first.StateTime(TaskStarted).Add(time.Second)))
+
+	Expect(limiter.Stats()).To(Equal(RateLimiterStats{
+		Tasks:     2,
+		Delayed:   1,
+		TotalWait: time.Second,
+		MaxWait:   time.Second,
+	}))
+	Expect(limiter.Stats().AverageWait()).To(Equal(500 * time.Millisecond))
+}
+
+func (s *RateLimitSuite) TestStopWhileWaiting(t sweet.T) {
+	clock := glock.NewMockClock()
+	limiter := NewLeakyBucket(time.Second)
+	tr := NewTaskRunner(WithClock(clock), WithRateLimiter(limiter))
+
+	called := make(chan struct{}, 2)
+	f := func(task *Task, args ...interface{}) TaskResult {
+		called <- struct{}{}
+		return nil
+	}
+
+	tr.Run(f).Wait(0)
+	Expect(called).To(Receive())
+
+	task := tr.Run(f)
+	Expect(task.Stop()).To(BeNil())
+
+	res, err := task.Wait(0)
+	Expect(err).To(BeNil())
+	Expect(res.Err()).To(Equal(ErrCancelled))
+	Expect(task.Started()).ToNot(BeClosed())
+	Expect(called).ToNot(Receive())
+	Expect(limiter.Stats().Cancelled).To(Equal(uint64(1)))
+}
+
+func (s *RateLimitSuite) TestContextWhileWaiting(t sweet.T) {
+	clock := glock.NewMockClock()
+	limiter := NewLeakyBucket(time.Second)
+	tr := NewTaskRunner(WithClock(clock), WithRateLimiter(limiter))
+
+	f := func(task *Task, args ...interface{}) TaskResult {
+		return nil
+	}
+	tr.Run(f).Wait(0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	task := tr.RunWithContext(ctx, f)
+	cancel()
+
+	res, err := task.Wait(0)
+	Expect(err).To(BeNil())
+	Expect(res.Err()).To(Equal(ErrCancelled))
+}
+
+func (s *RateLimitSuite) TestCollector(t sweet.T) {
+	clock := glock.NewMockClock()
+	limiter := NewLeakyBucket(time.Second)
+	c := NewAsyncCollector(WithClock(clock), WithRateLimiter(limiter))
+
+	for i := 0; i < 3; i++ {
+		c.Run(func(task *Task, args ...interface{}) TaskResult {
+			return NewValueResult(args[0], nil)
+		}, i)
+	}
+
+	go func() {
+		clock.BlockingAdvance(time.Second)
+		clock.BlockingAdvance(time.Second)
+	}()
+
+	results, err := c.Wait(0)
+	Expect(err).To(BeNil())
+	Expect(results).To(HaveLen(3))
+
+	stats := limiter.Stats()
+	Expect(stats.Tasks).To(Equal(uint64(3)))
+	Expect(stats.Delayed).To(Equal(uint64(2)))
+}
+
+func (s *RateLimitSuite) TestPoolRunner(t sweet.T) {
+	clock := glock.NewMockClock()
+	limiter := NewLeakyBucket(time.Second)
+	pr := NewPoolRunner(1, 2, WithClock(clock), WithRateLimiter(limiter))
+	defer pr.Close()
+
+	f := func(task *Task, args ...interface{}) TaskResult {
+		return nil
+	}
+
+	first, err := pr.Submit(f)
+	Expect(err).To(BeNil())
+	second, err := pr.Submit(f)
+	Expect(err).To(BeNil())
+
+	Eventually(first.Finished()).Should(BeClosed())
+	Consistently(second.Started()).ShouldNot(BeClosed())
+
+	clock.BlockingAdvance(time.Second)
+	Eventually(second.Finished()).Should(BeClosed())
+}
@@ -0,0 +1,137 @@
+package boom
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Backoff is the signature for the function used to determine how long to wait
+// before retrying a task's function. attempt is the number of the attempt that
+// just failed, starting at 1.
+type Backoff func(attempt int) time.Duration
+
+// ConstantBackoff returns a Backoff that always waits for d.
+func ConstantBackoff(d time.Duration) Backoff {
+	return func(attempt int) time.Duration {
+		return d
+	}
+}
+
+// ExponentialBackoff returns a Backoff that waits for base after the first attempt
+// and doubles the wait after each attempt after that, up to max. If max is 0 the
+// wait is not limited.
+func ExponentialBackoff(base time.Duration, max time.Duration) Backoff {
+	return func(attempt int) time.Duration {
+		d := base
+		for i := 1; i < attempt; i++ {
+			if max > 0 && d >= max {
+				break
+			}
+			// Stop doubling before the duration overflows
+			if d > math.MaxInt64/2 {
+				break
+			}
+			d *= 2
+		}
+
+		if max > 0 && d > max {
+			return max
+		}
+		return d
+	}
+}
+
+// JitteredBackoff returns a Backoff that randomly adjusts the wait returned by
+// backoff by up to the given fraction in either direction. For example, a jitter
+// of 0.1 turns a 100ms wait into a wait between 90ms and 110ms.
+func JitteredBackoff(backoff Backoff, jitter float64) Backoff {
+	return func(attempt int) time.Duration {
+		d := backoff(attempt)
+		delta := jitter * float64(d)
+		return d - time.Duration(delta) + time.Duration(rand.Float64()*2*delta)
+	}
+}
+
+// RetryPolicy determines when and how often a task's function is retried.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times the function is called, including
+	// the first attempt. If MaxAttempts is 0 the function is retried until it
+	// succeeds or the task is stopped.
+	MaxAttempts int
+	// Backoff determines how long to wait between attempts. If Backoff is nil the
+	// function is retried immediately.
+	Backoff Backoff
+	// Retryable returns whether an attempt that failed with err should be retried.
+	// If Retryable is nil every error is retried.
+	Retryable func(err error) bool
+}
+
+// WithRetry retries the function of every task when its result has an error,
+// according to the given policy.
+func WithRetry(policy RetryPolicy) TaskConfig {
+	return func(cfg *taskConfig) {
+		cfg.retry = &policy
+	}
+}
+
+// Retry wraps f so that it's retried according to the given policy. Waits between
+// attempts use the clock of the task the function is executed by.
+func Retry(policy RetryPolicy, f TaskFunc) TaskFunc {
+	return func(task *Task, args ...interface{}) TaskResult {
+		return policy.run(task, f, args...)
+	}
+}
+
+func (p *RetryPolicy) run(task *Task, f TaskFunc, args ...interface{}) TaskResult {
+	for attempt := 1; ; attempt++ {
+		task.setAttempts(attempt)
+
+		res := f(task, args...)
+		if !p.shouldRetry(res, attempt) {
+			return res
+		}
+
+		if !p.wait(task, attempt) {
+			return res
+		}
+	}
+}
+
+// wait blocks until the next attempt should be made, returning false if the
+// task is stopped before then.
+func (p *RetryPolicy) wait(task *Task, attempt int) bool {
+	var d time.Duration
+	if p.Backoff != nil {
+		d = p.Backoff(attempt)
+	}
+
+	if d <= 0 {
+		select {
+		case <-task.Stopping():
+			return false
+		default:
+			return true
+		}
+	}
+
+	select {
+	case <-task.cfg.clock.After(d):
+		return true
+	case <-task.Stopping():
+		return false
+	}
+}
+
+func (p *RetryPolicy) shouldRetry(res TaskResult, attempt int) bool {
+	if res == nil || res.Err() == nil {
+		return false
+	}
+	if p.MaxAttempts > 0 && attempt >= p.MaxAttempts {
+		return false
+	}
+	if p.Retryable != nil && !p.Retryable(res.Err()) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,125 @@
+package boom
+
+import (
+	"errors"
+	"time"
+
+	"github.com/aphistic/sweet"
+	"github.com/efritz/glock"
+	. "github.com/onsi/gomega"
+)
+
+type RetrySuite struct{}
+
+func (s *RetrySuite) TestConstantBackoff(t sweet.T) {
+	backoff := ConstantBackoff(time.Second)
+	Expect(backoff(1)).To(Equal(time.Second))
+	Expect(backoff(5)).To(Equal(time.Second))
+}
+
+func (s *RetrySuite) TestExponentialBackoff(t sweet.T) {
+	backoff := ExponentialBackoff(time.Second, 10*time.Second)
+	Expect(backoff(1)).To(Equal(time.Second))
+	Expect(backoff(2)).To(Equal(2 * time.Second))
+	Expect(backoff(3)).To(Equal(4 * time.Second))
+	Expect(backoff(4)).To(Equal(8 * time.Second))
+	Expect(backoff(5)).To(Equal(10 * time.Second))
+	Expect(backoff(100)).To(Equal(10 * time.Second))
+
+	backoff = ExponentialBackoff(time.Second, 0)
+	Expect(backoff(100)).To(BeNumerically(">", 0))
+
+	// Doubling stops at the largest duration that doesn't overflow
+	backoff = ExponentialBackoff(time.Duration(1<<61), 0)
+	Expect(backoff(2)).To(Equal(time.Duration(1 << 62)))
+	Expect(backoff(3)).To(Equal(time.Duration(1 << 62)))
+	Expect(backoff(100)).To(Equal(time.Duration(1 << 62)))
+}
+
+func (s *RetrySuite) TestJitteredBackoff(t sweet.T) {
+	backoff := JitteredBackoff(ConstantBackoff(100*time.Millisecond), 0.1)
+	for i := 0; i < 100; i++ {
+		Expect(backoff(1)).To(BeNumerically(">=", 90*time.Millisecond))
+		Expect(backoff(1)).To(BeNumerically("<=", 110*time.Millisecond))
+	}
+}
+
+func (s *RetrySuite) TestWithRetry(t sweet.T) {
+	clock := glock.NewMockClock()
+
+	tr := NewTaskRunner(WithClock(clock), WithRetry(RetryPolicy{
+		MaxAttempts: 3,
+		Backoff:     ConstantBackoff(time.Second),
+	}))
+
+	calls := 0
+	task := tr.Run(func(task *Task, args ...interface{}) TaskResult {
+		calls++
+		if calls < 3 {
+			return NewErrorResult(errors.New("I'm an error! - Ralph"))
+		}
+		return NewValueResult(calls, nil)
+	})
+
+	go clock.BlockingAdvance(time.Second)
+	Consistently(task.Finished()).ShouldNot(BeClosed())
+	go clock.BlockingAdvance(time.Second)
+
+	res, err := task.Wait(0)
+	Expect(err).To(BeNil())
+	Expect(res).To(Equal(NewValueResult(3, nil)))
+	Expect(task.Attempts()).To(Equal(3))
+}
+
+func (s *RetrySuite) TestRetryMaxAttempts(t sweet.T) {
+	tr := NewTaskRunner()
+
+	task := tr.Run(Retry(RetryPolicy{MaxAttempts: 2}, func(task *Task, args ...interface{}) TaskResult {
+		return NewErrorResult(errors.New("I'm an error! - Ralph"))
+	}))
+
+	res, err := task.Wait(time.Second)
+	Expect(err).To(BeNil())
+	Expect(res).To(Equal(NewErrorResult(errors.New("I'm an error! - Ralph"))))
+	Expect(task.Attempts()).To(Equal(2))
+}
+
+func (s *RetrySuite) TestRetryable(t sweet.T) {
+	errFatal := errors.New("fatal")
+	tr := NewTaskRunner(WithRetry(RetryPolicy{
+		Retryable: func(err error) bool {
+			return err != errFatal
+		},
+	}))
+
+	task := tr.Run(func(task *Task, args ...interface{}) TaskResult {
+		if task.Attempts() < 2 {
+			return NewErrorResult(errors.New("I'm an error! - Ralph"))
+		}
+		return NewErrorResult(errFatal)
+	})
+
+	res, err := task.Wait(time.Second)
+	Expect(err).To(BeNil())
+	Expect(res).To(Equal(NewErrorResult(errFatal)))
+	Expect(task.Attempts()).To(Equal(2))
+}
+
+func (s *RetrySuite) TestRetryStopping(t sweet.T) {
+	clock := glock.NewMockClock()
+
+	tr := NewTaskRunner(WithClock(clock), WithRetry(RetryPolicy{
+		Backoff: ConstantBackoff(time.Minute),
+	}))
+
+	task := tr.Run(func(task *Task, args ...interface{}) TaskResult {
+		return NewErrorResult(errors.New("I'm an error! - Ralph"))
+	})
+
+	Consistently(task.Finished()).ShouldNot(BeClosed())
+
+	res, err := task.StopAndWait(0)
+	Expect(err).To(BeNil())
+	Expect(res).To(Equal(NewErrorResult(errors.New("I'm an error! - Ralph"))))
+	Expect(task.Attempts()).To(Equal(1))
+}
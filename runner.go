@@ -2,12 +2,19 @@ package boom
 
 import (
 	"context"
+	"sync"
+	"time"
 )
 
 // TaskRunner is a way to start one-off tasks where the collection of results
 // does not matter, only each individual task.
 type TaskRunner struct {
 	cfg *taskConfig
+
+	lock      sync.Mutex
+	tasks     map[*Task]struct{}
+	schedules map[*Schedule]struct{}
+	shutdown  bool
 }
 
 // NewTaskRunner creates a new TaskRunner instance.
@@ -15,28 +22,194 @@ func NewTaskRunner(configs ...TaskConfig) *TaskRunner {
 	cfg := newTaskConfig()
 	cfg.ApplyConfigs(configs)
 
+	return newTaskRunner(cfg)
+}
+
+func newTaskRunner(cfg *taskConfig) *TaskRunner {
 	return &TaskRunner{
-		cfg: cfg,
+		cfg:       cfg,
+		tasks:     make(map[*Task]struct{}),
+		schedules: make(map[*Schedule]struct{}),
+	}
+}
+
+// newTask creates a new task tracked by the runner until it finishes. If the
+// runner has been shut down, the returned task is already finished with an
+// ErrRunnerClosed result.
+func (tr *TaskRunner) newTask(ctx context.Context, cfg *taskConfig, f TaskFunc, args ...interface{}) *Task {
+	task := newTask(ctx, cfg, f, args...)
+
+	tr.lock.Lock()
+	defer tr.lock.Unlock()
+
+	if tr.shutdown {
+		task.schedule()
+		task.skip(NewErrorResult(ErrRunnerClosed))
+		return task
 	}
+
+	task.onFinish = tr.untrack
+	tr.tasks[task] = struct{}{}
+
+	return task
+}
+
+func (tr *TaskRunner) runTask(ctx context.Context, cfg *taskConfig, f TaskFunc, args ...interface{}) *Task {
+	task := tr.newTask(ctx, cfg, f, args...)
+	if cfg.rateLimiter == nil {
+		task.Start()
+		return task
+	}
+
+	task.schedule()
+	go task.startLimited()
+
+	return task
+}
+
+func (tr *TaskRunner) untrack(task *Task) {
+	tr.lock.Lock()
+	defer tr.lock.Unlock()
+
+	delete(tr.tasks, task)
 }
 
 // New creates a new task with the given function and arguments
 func (tr *TaskRunner) New(f TaskFunc, args ...interface{}) *Task {
-	return newTask(context.Background(), tr.cfg, f, args...)
+	return tr.newTask(context.Background(), tr.cfg, f, args...)
 }
 
 // NewWithContext creates a new task with the given context, function and arguments
 func (tr *TaskRunner) NewWithContext(ctx context.Context, f TaskFunc, args ...interface{}) *Task {
-	return newTask(ctx, tr.cfg, f, args...)
+	return tr.newTask(ctx, tr.cfg, f, args...)
+}
+
+// NewWithConfig creates a new task with the given context, function and arguments.
+// The configs are applied to this task only, on top of the runner's configuration.
+func (tr *TaskRunner) NewWithConfig(ctx context.Context, configs []TaskConfig, f TaskFunc, args ...interface{}) *Task {
+	return tr.newTask(ctx, tr.cfg.with(configs), f, args...)
 }
 
 // Run will create a new task and immediately call Start to begin
 // execution of the task.
 func (tr *TaskRunner) Run(f TaskFunc, args ...interface{}) *Task {
-	return runTask(context.Background(), tr.cfg, f, args...)
+	return tr.runTask(context.Background(), tr.cfg, f, args...)
 }
 
 // RunWithContext calls Run using the provided context.Context for the task
 func (tr *TaskRunner) RunWithContext(ctx context.Context, f TaskFunc, args ...interface{}) *Task {
-	return runTask(ctx, tr.cfg, f, args...)
+	return tr.runTask(ctx, tr.cfg, f, args...)
+}
+
+// RunWithConfig calls Run using the provided context.Context for the task and
+// applying the configs to this task only, on top of the runner's configuration.
+func (tr *TaskRunner) RunWithConfig(ctx context.Context, configs []TaskConfig, f TaskFunc, args ...interface{}) *Task {
+	return tr.runTask(ctx, tr.cfg.with(configs), f, args...)
+}
+
+// RunAfter creates a new task and starts it once d has elapsed on the runner's
+// clock. The task's Started channel is closed when it starts. If the task is
+// stopped before then it never starts and its result is an ErrorResult with
+// ErrCancelled.
+func (tr *TaskRunner) RunAfter(d time.Duration, f TaskFunc, args ...interface{}) *Task {
+	return tr.RunAtWithConfig(context.Background(), nil, tr.cfg.clock.Now().Add(d), f, args...)
+}
+
+// RunAt creates a new task and starts it at the given time on the runner's
+// clock, in the same way as RunAfter.
+func (tr *TaskRunner) RunAt(t time.Time, f TaskFunc, args ...interface{}) *Task {
+	return tr.RunAtWithConfig(context.Background(), nil, t, f, args...)
+}
+
+// RunAtWithConfig calls RunAt using the provided context.Context for the task
+// and applying the configs to this task only, on top of the runner's
+// configuration. The task is cancelled before it starts if ctx is done.
+func (tr *TaskRunner) RunAtWithConfig(ctx context.Context, configs []TaskConfig, t time.Time, f TaskFunc, args ...interface{}) *Task {
+	task := tr.newTask(ctx, tr.cfg.with(configs), f, args...)
+	task.schedule()
+
+	go startAt(task, t)
+
+	return task
+}
+
+// startAt waits until the given time on the task's clock and starts the task,
+// or skips it if the task is stopped first.
+func startAt(task *Task, t time.Time) {
+	if delay := t.Sub(task.cfg.clock.Now()); delay > 0 {
+		select {
+		case <-task.cfg.clock.After(delay):
+		case <-task.Stopping():
+		}
+	}
+
+	task.startLimited()
+}
+
+// Tasks returns the tasks created by the runner that haven't finished yet,
+// in the order they were created.
+func (tr *TaskRunner) Tasks() []*Task {
+	tr.lock.Lock()
+	defer tr.lock.Unlock()
+
+	tasks := make([]*Task, 0, len(tr.tasks))
+	for task := range tr.tasks {
+		tasks = append(tasks, task)
+	}
+	sortTasks(tasks)
+
+	return tasks
+}
+
+// Shutdown stops the runner from accepting new tasks, stops all of the tasks
+// it has created and waits for the tasks that were started to finish. Tasks
+// created after Shutdown is called finish right away with ErrRunnerClosed.
+// Schedules created by the runner are stopped as well. If ctx is done before
+// every task has finished, a *ShutdownError listing the unfinished tasks is
+// returned.
+func (tr *TaskRunner) Shutdown(ctx context.Context) error {
+	tr.lock.Lock()
+	tr.shutdown = true
+	tasks := make([]*Task, 0, len(tr.tasks))
+	for task := range tr.tasks {
+		tasks = append(tasks, task)
+	}
+	schedules := make([]*Schedule, 0, len(tr.schedules))
+	for s := range tr.schedules {
+		schedules = append(schedules, s)
+	}
+	tr.lock.Unlock()
+
+	for _, s := range schedules {
+		s.Stop()
+	}
+
+	for _, task := range tasks {
+		// Tasks that haven't been started yet are cancelled as well so they
+		// stop right away if they're started later.
+		task.cancelCtx()
+	}
+
+	unfinished := make([]*Task, 0)
+	for _, task := range tasks {
+		if !task.executing() {
+			continue
+		}
+
+		select {
+		case <-task.Finished():
+		case <-ctx.Done():
+		}
+
+		select {
+		case <-task.Finished():
+		default:
+			unfinished = append(unfinished, task)
+		}
+	}
+
+	if len(unfinished) > 0 {
+		return &ShutdownError{Tasks: unfinished}
+	}
+	return nil
 }
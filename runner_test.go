@@ -2,6 +2,7 @@ package boom
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/aphistic/sweet"
@@ -101,3 +102,278 @@ func (s *RunnerSuite) TestRunWithContext(t sweet.T) {
 
 	Expect(taskCtx.Value("test")).To(Equal(1234))
 }
+
+func (s *RunnerSuite) TestTaskTimeout(t sweet.T) {
+	clock := glock.NewMockClockAt(time.Unix(100, 0))
+
+	deadlineChan := make(chan time.Time, 1)
+	ctxErr := make(chan error, 1)
+
+	tr := NewTaskRunner(WithClock(clock), WithTaskTimeout(10*time.Second))
+	task := tr.Run(func(task *Task, args ...interface{}) TaskResult {
+		if deadline, ok := task.Context().Deadline(); ok {
+			deadlineChan <- deadline
+		}
+		<-task.Stopping()
+		ctxErr <- task.Context().Err()
+		return NewValueResult(1, nil)
+	})
+
+	go clock.BlockingAdvance(10 * time.Second)
+
+	res, err := task.Wait(0)
+	Expect(err).To(BeNil())
+	Expect(res).To(Equal(NewErrorResult(ErrDeadlineExceeded)))
+	Eventually(deadlineChan).Should(Receive(Equal(time.Unix(110, 0))))
+	Eventually(ctxErr).Should(Receive(Equal(context.DeadlineExceeded)))
+}
+
+func (s *RunnerSuite) TestTaskTimeoutFinishes(t sweet.T) {
+	clock := glock.NewMockClockAt(time.Unix(100, 0))
+
+	tr := NewTaskRunner(WithClock(clock), WithTaskTimeout(10*time.Second))
+	task := tr.Run(func(task *Task, args ...interface{}) TaskResult {
+		return NewValueResult(1, nil)
+	})
+
+	res, err := task.Wait(0)
+	Expect(err).To(BeNil())
+	Expect(res).To(Equal(NewValueResult(1, nil)))
+	Expect(task.Context().Err()).To(BeNil())
+}
+
+func (s *RunnerSuite) TestTaskTimeoutWaitsForFunc(t sweet.T) {
+	clock := glock.NewMockClockAt(time.Unix(100, 0))
+
+	release := make(chan struct{})
+	tr := NewTaskRunner(WithClock(clock), WithTaskTimeout(10*time.Second))
+	task := tr.Run(func(task *Task, args ...interface{}) TaskResult {
+		<-release
+		return NewValueResult(1, nil)
+	})
+
+	clock.BlockingAdvance(10 * time.Second)
+	Eventually(task.Stopping()).Should(BeClosed())
+	Consistently(task.Finished()).ShouldNot(BeClosed())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := tr.Shutdown(ctx)
+	Expect(err).To(BeAssignableToTypeOf(&ShutdownError{}))
+	Expect(err.(*ShutdownError).Tasks).To(ConsistOf(BeIdenticalTo(task)))
+
+	close(release)
+
+	res, err := task.Wait(0)
+	Expect(err).To(BeNil())
+	Expect(res).To(Equal(NewErrorResult(ErrDeadlineExceeded)))
+}
+
+func (s *RunnerSuite) TestRunWithConfig(t sweet.T) {
+	clock := glock.NewMockClockAt(time.Unix(100, 0))
+
+	tr := NewTaskRunner(WithClock(clock))
+	task := tr.RunWithConfig(context.Background(), []TaskConfig{WithTaskTimeout(time.Second)}, func(task *Task, args ...interface{}) TaskResult {
+		Expect(args).To(Equal([]interface{}{1, 2}))
+		<-task.Stopping()
+		return NewValueResult(1, nil)
+	}, 1, 2)
+
+	Expect(task.cfg.taskTimeout).To(Equal(time.Second))
+	Expect(tr.cfg.taskTimeout).To(BeZero())
+
+	go clock.BlockingAdvance(time.Second)
+
+	res, err := task.Wait(0)
+	Expect(err).To(BeNil())
+	Expect(res).To(Equal(NewErrorResult(ErrDeadlineExceeded)))
+}
+
+func (s *RunnerSuite) TestTasks(t sweet.T) {
+	tr := NewTaskRunner()
+
+	release := make(chan struct{})
+	task := tr.Run(func(task *Task, args ...interface{}) TaskResult {
+		<-release
+		return nil
+	})
+	unstarted := tr.New(func(task *Task, args ...interface{}) TaskResult {
+		return nil
+	})
+
+	Expect(tr.Tasks()).To(ConsistOf(BeIdenticalTo(task), BeIdenticalTo(unstarted)))
+
+	close(release)
+	task.Wait(time.Second)
+
+	Eventually(tr.Tasks).Should(ConsistOf(BeIdenticalTo(unstarted)))
+}
+
+func (s *RunnerSuite) TestShutdown(t sweet.T) {
+	tr := NewTaskRunner()
+
+	task := tr.Run(func(task *Task, args ...interface{}) TaskResult {
+		<-task.Stopping()
+		return NewValueResult(1, nil)
+	})
+	unstarted := tr.New(func(task *Task, args ...interface{}) TaskResult {
+		return nil
+	})
+
+	err := tr.Shutdown(context.Background())
+	Expect(err).To(BeNil())
+	Expect(task.Finished()).To(BeClosed())
+	Expect(unstarted.Stopping()).To(BeClosed())
+
+	res, err := task.Wait(time.Second)
+	Expect(err).To(BeNil())
+	Expect(res).To(Equal(NewValueResult(1, nil)))
+
+	task = tr.Run(func(task *Task, args ...interface{}) TaskResult {
+		return NewValueResult(2, nil)
+	})
+	res, err = task.Wait(time.Second)
+	Expect(err).To(BeNil())
+	Expect(res).To(Equal(NewErrorResult(ErrRunnerClosed)))
+	Expect(task.Started()).ToNot(BeClosed())
+}
+
+func (s *RunnerSuite) TestShutdownTimeout(t sweet.T) {
+	tr := NewTaskRunner()
+
+	release := make(chan struct{})
+	stuck := tr.Run(func(task *Task, args ...interface{}) TaskResult {
+		<-release
+		return nil
+	})
+	tr.Run(func(task *Task, args ...interface{}) TaskResult {
+		<-task.Stopping()
+		return nil
+	}).Discard()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := tr.Shutdown(ctx)
+	Expect(err).To(BeAssignableToTypeOf(&ShutdownError{}))
+	Expect(err.(*ShutdownError).Tasks).To(ConsistOf(BeIdenticalTo(stuck)))
+	Expect(err.Error()).To(Equal(fmt.Sprintf("1 tasks did not finish before shutdown: task %d", stuck.ID())))
+
+	close(release)
+	stuck.Wait(time.Second)
+}
+
+func (s *RunnerSuite) TestNameAndLabels(t sweet.T) {
+	tr := NewTaskRunner(WithName("default"), WithLabels(map[string]string{"service": "api"}))
+
+	task := tr.NewWithConfig(context.Background(), []TaskConfig{
+		WithName("fetch"),
+		WithLabels(map[string]string{"user": "ralph"}),
+	}, func(task *Task, args ...interface{}) TaskResult {
+		return nil
+	})
+	Expect(task.Name()).To(Equal("fetch"))
+	Expect(task.Labels()).To(Equal(map[string]string{"service": "api", "user": "ralph"}))
+
+	task = tr.New(func(task *Task, args ...interface{}) TaskResult {
+		return nil
+	})
+	Expect(task.Name()).To(Equal("default"))
+	Expect(task.Labels()).To(Equal(map[string]string{"service": "api"}))
+}
+
+func (s *RunnerSuite) TestRunAfter(t sweet.T) {
+	clock := glock.NewMockClock()
+	tr := NewTaskRunner(WithClock(clock))
+
+	task := tr.RunAfter(time.Minute, func(task *Task, args ...interface{}) TaskResult {
+		return NewValueResult(args[0], nil)
+	}, "value")
+
+	Expect(tr.Tasks()).To(ConsistOf(BeIdenticalTo(task)))
+	Consistently(task.Started()).ShouldNot(BeClosed())
+
+	clock.BlockingAdvance(time.Minute)
+	Eventually(task.Started()).Should(BeClosed())
+
+	res, err := task.Wait(0)
+	Expect(err).To(BeNil())
+	Expect(res).To(Equal(NewValueResult("value", nil)))
+}
+
+func (s *RunnerSuite) TestRunAt(t sweet.T) {
+	clock := glock.NewMockClockAt(time.Unix(100, 0))
+	tr := NewTaskRunner(WithClock(clock))
+
+	task := tr.RunAt(time.Unix(160, 0), func(task *Task, args ...interface{}) TaskResult {
+		return nil
+	})
+
+	clock.BlockingAdvance(59 * time.Second)
+	Consistently(task.Started()).ShouldNot(BeClosed())
+
+	clock.Advance(time.Second)
+	Eventually(task.Finished()).Should(BeClosed())
+	Expect(task.StateTime(TaskStarted)).To(Equal(time.Unix(160, 0)))
+}
+
+func (s *RunnerSuite) TestRunAtPast(t sweet.T) {
+	clock := glock.NewMockClockAt(time.Unix(100, 0))
+	tr := NewTaskRunner(WithClock(clock))
+
+	task := tr.RunAt(time.Unix(50, 0), func(task *Task, args ...interface{}) TaskResult {
+		return nil
+	})
+
+	Eventually(task.Finished()).Should(BeClosed())
+}
+
+func (s *RunnerSuite) TestRunAfterStop(t sweet.T) {
+	clock := glock.NewMockClock()
+	tr := NewTaskRunner(WithClock(clock))
+
+	called := make(chan struct{})
+	task := tr.RunAfter(time.Minute, func(task *Task, args ...interface{}) TaskResult {
+		close(called)
+		return nil
+	})
+
+	Expect(task.Stop()).To(BeNil())
+
+	res, err := task.Wait(0)
+	Expect(err).To(BeNil())
+	Expect(res.Err()).To(Equal(ErrCancelled))
+	Expect(task.Started()).ToNot(BeClosed())
+	Expect(called).ToNot(BeClosed())
+	Expect(tr.Tasks()).To(BeEmpty())
+}
+
+func (s *RunnerSuite) TestRunAtWithConfigContext(t sweet.T) {
+	clock := glock.NewMockClock()
+	tr := NewTaskRunner(WithClock(clock))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	task := tr.RunAtWithConfig(ctx, []TaskConfig{WithName("delayed")}, clock.Now().Add(time.Minute), func(task *Task, args ...interface{}) TaskResult {
+		return nil
+	})
+	Expect(task.Name()).To(Equal("delayed"))
+
+	cancel()
+
+	res, err := task.Wait(0)
+	Expect(err).To(BeNil())
+	Expect(res.Err()).To(Equal(ErrCancelled))
+}
+
+func (s *RunnerSuite) TestRunAfterShutdown(t sweet.T) {
+	clock := glock.NewMockClock()
+	tr := NewTaskRunner(WithClock(clock))
+
+	task := tr.RunAfter(time.Minute, func(task *Task, args ...interface{}) TaskResult {
+		return nil
+	})
+
+	Expect(tr.Shutdown(context.Background())).To(BeNil())
+	Expect(task.Finished()).To(BeClosed())
+	Expect(task.Started()).ToNot(BeClosed())
+}
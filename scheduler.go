@@ -0,0 +1,280 @@
+package boom
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Trigger determines when a scheduled task runs.
+type Trigger interface {
+	// Next returns the first time after t the task should run, or the zero
+	// time if it shouldn't run again.
+	Next(t time.Time) time.Time
+}
+
+// everyTrigger is a Trigger that fires at a fixed interval
+type everyTrigger time.Duration
+
+// Every returns a Trigger that fires every interval. It panics if the interval
+// isn't positive.
+func Every(interval time.Duration) Trigger {
+	if interval <= 0 {
+		panic("boom: non-positive interval for Every")
+	}
+	return everyTrigger(interval)
+}
+
+func (e everyTrigger) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(e))
+}
+
+// OverlapPolicy determines what a Schedule does when it's time to run its task
+// while the previous run is still executing.
+type OverlapPolicy int
+
+const (
+	// OverlapSkip skips the run
+	OverlapSkip OverlapPolicy = iota
+	// OverlapQueue queues the run to start once the previous runs finish
+	OverlapQueue
+	// OverlapAllow starts the run while the previous runs are still executing
+	OverlapAllow
+)
+
+// WithOverlapPolicy sets what a Schedule does when it's time to run its task
+// while the previous run is still executing. The default is OverlapSkip.
+func WithOverlapPolicy(policy OverlapPolicy) TaskConfig {
+	return func(cfg *taskConfig) {
+		cfg.overlapPolicy = policy
+	}
+}
+
+// WithJitter delays each run of a Schedule by a random duration up to jitter,
+// which spreads out tasks that would otherwise all run at the same time.
+func WithJitter(jitter time.Duration) TaskConfig {
+	return func(cfg *taskConfig) {
+		cfg.jitter = jitter
+	}
+}
+
+// Schedule runs a task each time its Trigger fires until it's stopped. Each
+// run is a new task created by the TaskRunner the Schedule was created with.
+type Schedule struct {
+	runner  *TaskRunner
+	cfg     *taskConfig
+	trigger Trigger
+
+	f    TaskFunc
+	args []interface{}
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	lock     sync.Mutex
+	paused   bool
+	stopped  bool
+	running  int
+	pending  int
+	next     time.Time
+	doneChan chan struct{}
+}
+
+// Schedule runs f with args each time trigger fires until the returned
+// Schedule is stopped or the runner is shut down.
+func (tr *TaskRunner) Schedule(trigger Trigger, f TaskFunc, args ...interface{}) *Schedule {
+	return tr.ScheduleWithConfig(context.Background(), nil, trigger, f, args...)
+}
+
+// ScheduleWithConfig calls Schedule, using ctx as the parent context of each
+// run and applying configs to the schedule on top of the runner's configuration.
+// The schedule stops when ctx is done.
+func (tr *TaskRunner) ScheduleWithConfig(ctx context.Context, configs []TaskConfig, trigger Trigger, f TaskFunc, args ...interface{}) *Schedule {
+	ctx, cancel := context.WithCancel(ctx)
+
+	s := &Schedule{
+		runner:   tr,
+		cfg:      tr.cfg.with(configs),
+		trigger:  trigger,
+		f:        f,
+		args:     args,
+		ctx:      ctx,
+		cancel:   cancel,
+		doneChan: make(chan struct{}),
+	}
+
+	tr.lock.Lock()
+	defer tr.lock.Unlock()
+
+	if tr.shutdown {
+		cancel()
+	} else {
+		tr.schedules[s] = struct{}{}
+	}
+
+	go s.loop()
+
+	return s
+}
+
+// loop waits for the trigger to fire and runs the task until the schedule is stopped
+func (s *Schedule) loop() {
+	defer s.finish()
+
+	next := s.trigger.Next(s.cfg.clock.Now())
+	for !next.IsZero() {
+		s.setNext(next)
+
+		delay := next.Sub(s.cfg.clock.Now()) + s.jitter()
+		if delay > 0 {
+			select {
+			case <-s.cfg.clock.After(delay):
+			case <-s.ctx.Done():
+				return
+			}
+		} else if s.ctx.Err() != nil {
+			return
+		}
+
+		s.fire()
+
+		// If runs were missed because the clock moved past them, skip ahead
+		// to the first run that's still in the future.
+		now := s.cfg.clock.Now()
+		next = s.trigger.Next(next)
+		for !next.IsZero() && !next.After(now) {
+			next = s.trigger.Next(next)
+		}
+	}
+}
+
+func (s *Schedule) jitter() time.Duration {
+	if s.cfg.jitter <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(s.cfg.jitter)))
+}
+
+// fire runs the task, unless the schedule is paused or the previous run is still
+// executing and the overlap policy doesn't allow it
+func (s *Schedule) fire() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.paused || s.stopped {
+		return
+	}
+
+	if s.running > 0 {
+		switch s.cfg.overlapPolicy {
+		case OverlapSkip:
+			return
+		case OverlapQueue:
+			s.pending++
+			return
+		}
+	}
+
+	s.start()
+}
+
+// start starts a run of the task. The schedule's lock must be held.
+func (s *Schedule) start() {
+	s.running++
+	task := s.runner.runTask(s.ctx, s.cfg, s.f, s.args...)
+	go s.wait(task)
+}
+
+// wait waits for a run to finish, starting the next queued run if there is one
+func (s *Schedule) wait(task *Task) {
+	// Nothing else receives the task's result, so Wait is needed to let the
+	// task's goroutine exit
+	task.Wait(0)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.running--
+	if s.pending > 0 && !s.stopped {
+		s.pending--
+		s.start()
+		return
+	}
+
+	if s.stopped && s.running == 0 {
+		close(s.doneChan)
+	}
+}
+
+// finish marks the schedule as stopped once its loop has exited
+func (s *Schedule) finish() {
+	s.cancel()
+
+	s.runner.lock.Lock()
+	delete(s.runner.schedules, s)
+	s.runner.lock.Unlock()
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.stopped = true
+	s.pending = 0
+	s.next = time.Time{}
+	if s.running == 0 {
+		close(s.doneChan)
+	}
+}
+
+func (s *Schedule) setNext(next time.Time) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.next = next
+}
+
+// Next returns the time the task is next scheduled to run, not including
+// jitter, or the zero time if it won't run again.
+func (s *Schedule) Next() time.Time {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.next
+}
+
+// Pause stops the task from running when the trigger fires until Resume is
+// called. Runs that are already executing or queued aren't affected.
+func (s *Schedule) Pause() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.paused = true
+}
+
+// Resume allows the task to run again after Pause was called.
+func (s *Schedule) Resume() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.paused = false
+}
+
+// Paused returns true if the schedule is paused.
+func (s *Schedule) Paused() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.paused
+}
+
+// Stop stops the schedule. Queued runs are dropped and runs that are executing
+// are asked to stop.
+func (s *Schedule) Stop() {
+	s.cancel()
+}
+
+// Done returns a channel that is closed once the schedule has stopped and all
+// of its runs have finished.
+func (s *Schedule) Done() <-chan struct{} {
+	return s.doneChan
+}
@@ -0,0 +1,260 @@
+package boom
+
+import (
+	"context"
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	"github.com/aphistic/sweet"
+	"github.com/efritz/glock"
+	. "github.com/onsi/gomega"
+)
+
+type SchedulerSuite struct{}
+
+// countingTask returns a TaskFunc that counts how many times it's been
+// started and, if block is not nil, blocks until block is closed.
+func countingTask(count *int32, block <-chan struct{}) TaskFunc {
+	return func(task *Task, args ...interface{}) TaskResult {
+		atomic.AddInt32(count, 1)
+		if block != nil {
+			select {
+			case <-block:
+			case <-task.Stopping():
+			}
+		}
+		return nil
+	}
+}
+
+func loadCount(count *int32) func() int32 {
+	return func() int32 {
+		return atomic.LoadInt32(count)
+	}
+}
+
+func (s *SchedulerSuite) TestEvery(t sweet.T) {
+	clock := glock.NewMockClock()
+	tr := NewTaskRunner(WithClock(clock))
+
+	var count int32
+	sched := tr.Schedule(Every(time.Minute), countingTask(&count, nil))
+	defer sched.Stop()
+
+	Eventually(sched.Next).Should(Equal(clock.Now().Add(time.Minute)))
+	Expect(loadCount(&count)()).To(Equal(int32(0)))
+
+	for i := int32(1); i <= 3; i++ {
+		clock.BlockingAdvance(time.Minute)
+		Eventually(loadCount(&count)).Should(Equal(i))
+	}
+}
+
+func (s *SchedulerSuite) TestEveryPanics(t sweet.T) {
+	Expect(func() { Every(0) }).To(Panic())
+}
+
+func (s *SchedulerSuite) TestMissedRuns(t sweet.T) {
+	clock := glock.NewMockClock()
+	tr := NewTaskRunner(WithClock(clock))
+
+	var count int32
+	sched := tr.Schedule(Every(time.Minute), countingTask(&count, nil))
+	defer sched.Stop()
+
+	start := clock.Now()
+	clock.BlockingAdvance(5*time.Minute + time.Second)
+	Eventually(loadCount(&count)).Should(Equal(int32(1)))
+	Eventually(sched.Next).Should(Equal(start.Add(6 * time.Minute)))
+}
+
+func (s *SchedulerSuite) TestCron(t sweet.T) {
+	clock := glock.NewMockClockAt(time.Date(2020, 1, 1, 10, 0, 30, 0, time.UTC))
+	tr := NewTaskRunner(WithClock(clock))
+
+	var count int32
+	sched := tr.Schedule(MustCron("*/5 * * * *"), countingTask(&count, nil))
+	defer sched.Stop()
+
+	Eventually(sched.Next).Should(Equal(time.Date(2020, 1, 1, 10, 5, 0, 0, time.UTC)))
+
+	clock.BlockingAdvance(4*time.Minute + 30*time.Second)
+	Eventually(loadCount(&count)).Should(Equal(int32(1)))
+	Eventually(sched.Next).Should(Equal(time.Date(2020, 1, 1, 10, 10, 0, 0, time.UTC)))
+}
+
+func (s *SchedulerSuite) TestOverlapSkip(t sweet.T) {
+	clock := glock.NewMockClock()
+	tr := NewTaskRunner(WithClock(clock))
+
+	var count int32
+	block := make(chan struct{})
+	sched := tr.Schedule(Every(time.Minute), countingTask(&count, block))
+	defer sched.Stop()
+
+	clock.BlockingAdvance(time.Minute)
+	Eventually(loadCount(&count)).Should(Equal(int32(1)))
+
+	start := clock.Now()
+	clock.BlockingAdvance(time.Minute)
+	Eventually(sched.Next).Should(Equal(start.Add(2 * time.Minute)))
+	Expect(loadCount(&count)()).To(Equal(int32(1)))
+
+	close(block)
+	Eventually(tr.Tasks).Should(BeEmpty())
+
+	clock.BlockingAdvance(time.Minute)
+	Eventually(loadCount(&count)).Should(Equal(int32(2)))
+}
+
+func (s *SchedulerSuite) TestOverlapQueue(t sweet.T) {
+	clock := glock.NewMockClock()
+	tr := NewTaskRunner(WithClock(clock))
+
+	var count int32
+	block := make(chan struct{})
+	sched := tr.ScheduleWithConfig(
+		context.Background(),
+		[]TaskConfig{WithOverlapPolicy(OverlapQueue)},
+		Every(time.Minute),
+		countingTask(&count, block),
+	)
+	defer sched.Stop()
+
+	clock.BlockingAdvance(time.Minute)
+	Eventually(loadCount(&count)).Should(Equal(int32(1)))
+
+	start := clock.Now()
+	clock.BlockingAdvance(time.Minute)
+	clock.BlockingAdvance(time.Minute)
+	Eventually(sched.Next).Should(Equal(start.Add(3 * time.Minute)))
+	Expect(loadCount(&count)()).To(Equal(int32(1)))
+	Expect(tr.Tasks()).To(HaveLen(1))
+
+	close(block)
+	Eventually(loadCount(&count)).Should(Equal(int32(3)))
+}
+
+func (s *SchedulerSuite) TestOverlapAllow(t sweet.T) {
+	clock := glock.NewMockClock()
+	tr := NewTaskRunner(WithClock(clock), WithOverlapPolicy(OverlapAllow))
+
+	var count int32
+	block := make(chan struct{})
+	defer close(block)
+
+	sched := tr.Schedule(Every(time.Minute), countingTask(&count, block))
+	defer sched.Stop()
+
+	for i := int32(1); i <= 3; i++ {
+		clock.BlockingAdvance(time.Minute)
+		Eventually(loadCount(&count)).Should(Equal(i))
+	}
+	Expect(tr.Tasks()).To(HaveLen(3))
+}
+
+func (s *SchedulerSuite) TestPauseResume(t sweet.T) {
+	clock := glock.NewMockClock()
+	tr := NewTaskRunner(WithClock(clock))
+
+	var count int32
+	sched := tr.Schedule(Every(time.Minute), countingTask(&count, nil))
+	defer sched.Stop()
+
+	sched.Pause()
+	Expect(sched.Paused()).To(BeTrue())
+
+	start := clock.Now()
+	clock.BlockingAdvance(time.Minute)
+	Eventually(sched.Next).Should(Equal(start.Add(2 * time.Minute)))
+	Expect(loadCount(&count)()).To(Equal(int32(0)))
+
+	sched.Resume()
+	Expect(sched.Paused()).To(BeFalse())
+
+	clock.BlockingAdvance(time.Minute)
+	Eventually(loadCount(&count)).Should(Equal(int32(1)))
+}
+
+func (s *SchedulerSuite) TestJitter(t sweet.T) {
+	clock := glock.NewMockClock()
+	tr := NewTaskRunner(WithClock(clock), WithJitter(time.Second))
+
+	var count int32
+	sched := tr.Schedule(Every(time.Minute), countingTask(&count, nil))
+	defer sched.Stop()
+
+	clock.BlockingAdvance(time.Minute + time.Second)
+	Eventually(loadCount(&count)).Should(Equal(int32(1)))
+}
+
+func (s *SchedulerSuite) TestStop(t sweet.T) {
+	clock := glock.NewMockClock()
+	tr := NewTaskRunner(WithClock(clock))
+
+	var count int32
+	block := make(chan struct{})
+	sched := tr.Schedule(Every(time.Minute), countingTask(&count, block))
+
+	clock.BlockingAdvance(time.Minute)
+	Eventually(loadCount(&count)).Should(Equal(int32(1)))
+
+	sched.Stop()
+	Eventually(sched.Done()).Should(BeClosed())
+	Expect(sched.Next().IsZero()).To(BeTrue())
+	Expect(tr.Tasks()).To(BeEmpty())
+
+	clock.Advance(time.Minute)
+	Consistently(loadCount(&count)).Should(Equal(int32(1)))
+}
+
+func (s *SchedulerSuite) TestStopNoLeaks(t sweet.T) {
+	goroutines := runtime.NumGoroutine()
+
+	clock := glock.NewMockClock()
+	tr := NewTaskRunner(WithClock(clock))
+
+	var count int32
+	sched := tr.Schedule(Every(time.Minute), countingTask(&count, nil))
+
+	for i := int32(1); i <= 50; i++ {
+		clock.BlockingAdvance(time.Minute)
+		Eventually(loadCount(&count)).Should(Equal(i))
+	}
+
+	sched.Stop()
+	Eventually(sched.Done()).Should(BeClosed())
+	Eventually(runtime.NumGoroutine).Should(BeNumerically("<=", goroutines))
+}
+
+func (s *SchedulerSuite) TestContextDone(t sweet.T) {
+	clock := glock.NewMockClock()
+	tr := NewTaskRunner(WithClock(clock))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sched := tr.ScheduleWithConfig(ctx, nil, Every(time.Minute), func(task *Task, args ...interface{}) TaskResult {
+		return nil
+	})
+
+	cancel()
+	Eventually(sched.Done()).Should(BeClosed())
+}
+
+func (s *SchedulerSuite) TestShutdown(t sweet.T) {
+	clock := glock.NewMockClock()
+	tr := NewTaskRunner(WithClock(clock))
+
+	var count int32
+	block := make(chan struct{})
+	sched := tr.Schedule(Every(time.Minute), countingTask(&count, block))
+
+	clock.BlockingAdvance(time.Minute)
+	Eventually(loadCount(&count)).Should(Equal(int32(1)))
+
+	Expect(tr.Shutdown(context.Background())).To(BeNil())
+	Eventually(sched.Done()).Should(BeClosed())
+
+	after := tr.Schedule(Every(time.Minute), countingTask(&count, nil))
+	Eventually(after.Done()).Should(BeClosed())
+}
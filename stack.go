@@ -0,0 +1,99 @@
+package boom
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"sync/atomic"
+)
+
+// trackStacks is set once EnableTaskStacks has been called
+var trackStacks int32
+
+// EnableTaskStacks makes tasks record which goroutine is executing their
+// function so their stacks can be retrieved with Stack or TaskStacks. Finding
+// the goroutine adds a small cost to starting every task, so it's disabled
+// until this is called. Only functions that begin executing afterwards are
+// recorded.
+func EnableTaskStacks() {
+	atomic.StoreInt32(&trackStacks, 1)
+}
+
+// Stack returns the stack trace of the goroutine executing the task's
+// function, or nil if the function isn't executing or EnableTaskStacks
+// hadn't been called when it started. Getting the stack stops every goroutine
+// while the stacks are collected, so it's intended for debugging.
+func (t *Task) Stack() []byte {
+	return TaskStacks([]*Task{t})[t.ID()]
+}
+
+// TaskStacks returns the stack traces of the goroutines executing the
+// functions of the given tasks, keyed by task ID, using a single dump of
+// every goroutine. Tasks without a stack, as described by Stack, are left out.
+func TaskStacks(tasks []*Task) map[uint64][]byte {
+	goroutines := make(map[uint64]uint64, len(tasks))
+	for _, task := range tasks {
+		if id := atomic.LoadUint64(&task.goroutine); id != 0 {
+			goroutines[id] = task.ID()
+		}
+	}
+
+	stacks := make(map[uint64][]byte, len(goroutines))
+	if len(goroutines) == 0 {
+		return stacks
+	}
+
+	for _, stack := range bytes.Split(allStacks(), []byte("\n\n")) {
+		if taskID, ok := goroutines[goroutineID(stack)]; ok {
+			stacks[taskID] = stack
+		}
+	}
+
+	return stacks
+}
+
+// setGoroutine records the goroutine executing the task's function, or 0 once
+// the function has returned
+func (t *Task) setGoroutine(id uint64) {
+	atomic.StoreUint64(&t.goroutine, id)
+}
+
+// trackGoroutine records the calling goroutine as the one executing the
+// task's function if EnableTaskStacks has been called
+func (t *Task) trackGoroutine() {
+	if atomic.LoadInt32(&trackStacks) == 0 {
+		return
+	}
+
+	buf := make([]byte, 64)
+	t.setGoroutine(goroutineID(buf[:runtime.Stack(buf, false)]))
+}
+
+// goroutineID parses the ID of a goroutine from the first line of its stack
+// trace, returning 0 if it can't be parsed
+func goroutineID(stack []byte) uint64 {
+	stack = bytes.TrimPrefix(stack, []byte("goroutine "))
+
+	idx := bytes.IndexByte(stack, ' ')
+	if idx < 0 {
+		return 0
+	}
+
+	id, err := strconv.ParseUint(string(stack[:idx]), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+// allStacks returns the stack traces of every goroutine
+func allStacks() []byte {
+	buf := make([]byte, 64*1024)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n]
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
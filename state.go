@@ -0,0 +1,154 @@
+package boom
+
+import (
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// TaskState is the state of a task in its lifecycle
+type TaskState int
+
+const (
+	// TaskCreated means the task has been created but not started
+	TaskCreated TaskState = iota
+	// TaskStarted means the task has been started
+	TaskStarted
+	// TaskRunning means the task has been set to the 'Running' state using SetRunning
+	TaskRunning
+	// TaskStopping means the task has been asked to stop but hasn't finished
+	TaskStopping
+	// TaskFinished means the task has finished executing
+	TaskFinished
+
+	numTaskStates = iota
+)
+
+func (s TaskState) String() string {
+	switch s {
+	case TaskCreated:
+		return "created"
+	case TaskStarted:
+		return "started"
+	case TaskRunning:
+		return "running"
+	case TaskStopping:
+		return "stopping"
+	case TaskFinished:
+		return "finished"
+	default:
+		return "unknown"
+	}
+}
+
+// TaskSnapshot is a point in time view of a task, useful for debugging.
+type TaskSnapshot struct {
+	ID     uint64
+	Name   string
+	Labels map[string]string
+	State  TaskState
+
+	// Times contains the time, according to the task's clock, that the task
+	// entered each state it has been in.
+	Times map[TaskState]time.Time
+}
+
+// ID returns the task's unique ID
+func (t *Task) ID() uint64 {
+	return t.id
+}
+
+// Name returns the name given to the task using WithName
+func (t *Task) Name() string {
+	return t.name
+}
+
+// Labels returns a copy of the labels given to the task using WithLabels
+func (t *Task) Labels() map[string]string {
+	labels := make(map[string]string, len(t.labels))
+	for key, value := range t.labels {
+		labels[key] = value
+	}
+	return labels
+}
+
+// State returns the current state of the task
+func (t *Task) State() TaskState {
+	select {
+	case <-t.finishedChan:
+		return TaskFinished
+	default:
+	}
+
+	select {
+	case <-t.ctx.Done():
+		// The context may have been cancelled by its parent, in which
+		// case this is the first time the task has seen it stopping.
+		t.setState(TaskStopping)
+		return TaskStopping
+	default:
+	}
+
+	if atomic.LoadInt32(&t.running) == 1 {
+		return TaskRunning
+	}
+
+	select {
+	case <-t.startedChan:
+		return TaskStarted
+	default:
+		return TaskCreated
+	}
+}
+
+// StateTime returns the time, according to the task's clock, that the task
+// entered the given state. The zero time is returned if it never has.
+func (t *Task) StateTime(state TaskState) time.Time {
+	if state < 0 || state >= numTaskStates {
+		return time.Time{}
+	}
+
+	t.stateLock.RLock()
+	defer t.stateLock.RUnlock()
+
+	return t.stateTimes[state]
+}
+
+// Snapshot returns a TaskSnapshot of the task's current state
+func (t *Task) Snapshot() *TaskSnapshot {
+	snapshot := &TaskSnapshot{
+		ID:     t.id,
+		Name:   t.name,
+		Labels: t.Labels(),
+		State:  t.State(),
+		Times:  make(map[TaskState]time.Time),
+	}
+
+	t.stateLock.RLock()
+	defer t.stateLock.RUnlock()
+
+	for state, stateTime := range t.stateTimes {
+		if !stateTime.IsZero() {
+			snapshot.Times[TaskState(state)] = stateTime
+		}
+	}
+
+	return snapshot
+}
+
+// setState records the time the task first entered the given state
+func (t *Task) setState(state TaskState) {
+	t.stateLock.Lock()
+	defer t.stateLock.Unlock()
+
+	if t.stateTimes[state].IsZero() {
+		t.stateTimes[state] = t.cfg.clock.Now()
+	}
+}
+
+// sortTasks sorts tasks by their ID, which is the order they were created in
+func sortTasks(tasks []*Task) {
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].id < tasks[j].id
+	})
+}
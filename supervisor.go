@@ -0,0 +1,363 @@
+package boom
+
+import (
+	"sync"
+	"time"
+)
+
+// RestartStrategy determines which children a Supervisor restarts when one
+// of its children exits.
+type RestartStrategy int
+
+const (
+	// OneForOne restarts only the child that exited
+	OneForOne RestartStrategy = iota
+	// OneForAll stops every other child and restarts all of them
+	OneForAll
+	// RestForOne stops the children added after the one that exited and
+	// restarts the child along with them
+	RestForOne
+)
+
+// RestartType determines whether a Supervisor restarts a child when it exits.
+type RestartType int
+
+const (
+	// RestartPermanent always restarts the child
+	RestartPermanent RestartType = iota
+	// RestartTransient restarts the child only if its result has an error
+	RestartTransient
+	// RestartTemporary never restarts the child
+	RestartTemporary
+)
+
+// SupervisorPolicy determines how a Supervisor restarts its children.
+type SupervisorPolicy struct {
+	// Strategy determines which children are restarted when a child exits
+	Strategy RestartStrategy
+	// MaxRestarts is the number of restarts allowed within Window. If there are
+	// more, the Supervisor stops all of its children and exits with an
+	// ErrRestartIntensity result. If MaxRestarts is 0 restarts are not limited.
+	MaxRestarts int
+	// Window is the period of time MaxRestarts applies to, measured using the
+	// Supervisor's clock.
+	Window time.Duration
+	// Backoff determines how long to wait before restarting children. attempt is
+	// the number of restarts within Window, including this one. If Backoff is
+	// nil children are restarted immediately.
+	Backoff Backoff
+	// ShutdownTimeout is how long to wait for each child to finish after it's
+	// stopped, measured using the Supervisor's clock. If a child doesn't finish
+	// in time, the Supervisor stops its remaining children and exits with a
+	// *ShutdownError result listing the children that didn't finish. If
+	// ShutdownTimeout is 0 the Supervisor waits for children indefinitely.
+	ShutdownTimeout time.Duration
+}
+
+// Supervisor runs long-lived tasks as its children and restarts them when they
+// exit while the Supervisor is still running. Run is a TaskFunc, so a Supervisor
+// can be run by a TaskRunner or added as a child of another Supervisor to build
+// a supervision tree. When a nested Supervisor exceeds its restart intensity it
+// exits with an error, which is handled by its parent like any other child exiting.
+type Supervisor struct {
+	cfg    *taskConfig
+	policy SupervisorPolicy
+
+	lock     sync.Mutex
+	running  bool
+	children []*supervisorChild
+	names    map[string]*supervisorChild
+}
+
+type supervisorChild struct {
+	name    string
+	restart RestartType
+	f       TaskFunc
+	args    []interface{}
+
+	task       *Task
+	generation int
+}
+
+type childExit struct {
+	child      *supervisorChild
+	generation int
+	result     TaskResult
+}
+
+// NewSupervisor creates a new Supervisor using the given policy. The configs are
+// applied to every child task.
+func NewSupervisor(policy SupervisorPolicy, configs ...TaskConfig) *Supervisor {
+	cfg := newTaskConfig()
+	cfg.ApplyConfigs(configs)
+
+	return &Supervisor{
+		cfg:    cfg,
+		policy: policy,
+		names:  make(map[string]*supervisorChild),
+	}
+}
+
+// Add adds a child that runs f with args and is always restarted when it exits.
+// Children are started in the order they're added. The child's tasks are named
+// after the child.
+func (s *Supervisor) Add(name string, f TaskFunc, args ...interface{}) error {
+	return s.AddWithRestart(name, RestartPermanent, f, args...)
+}
+
+// AddWithRestart adds a child in the same way as Add, using restart to decide
+// whether the child is restarted when it exits. Children can't be added while
+// the Supervisor is running.
+func (s *Supervisor) AddWithRestart(name string, restart RestartType, f TaskFunc, args ...interface{}) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.running {
+		return ErrExecuting
+	}
+
+	if _, ok := s.names[name]; ok {
+		return ErrDuplicateChild
+	}
+
+	child := &supervisorChild{
+		name:    name,
+		restart: restart,
+		f:       f,
+		args:    args,
+	}
+	s.children = append(s.children, child)
+	s.names[name] = child
+
+	return nil
+}
+
+// Child returns the task currently running for the named child, or nil if the
+// child isn't running.
+func (s *Supervisor) Child(name string) *Task {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if child, ok := s.names[name]; ok {
+		return child.task
+	}
+	return nil
+}
+
+// Run starts the Supervisor's children and restarts them as they exit until task
+// is stopped, at which point the children are stopped and Run returns a nil
+// result once they've finished, or a *ShutdownError result if any of them
+// didn't finish within the policy's ShutdownTimeout.
+func (s *Supervisor) Run(task *Task, args ...interface{}) TaskResult {
+	s.lock.Lock()
+	if s.running {
+		s.lock.Unlock()
+		return NewErrorResult(ErrExecuting)
+	}
+	s.running = true
+	s.lock.Unlock()
+
+	defer func() {
+		s.lock.Lock()
+		s.running = false
+		s.lock.Unlock()
+	}()
+
+	runner := newTaskRunner(s.cfg)
+	exits := make(chan childExit)
+	done := make(chan struct{})
+	defer close(done)
+
+	start := func(child *supervisorChild) {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		child.generation++
+		child.task = runner.RunWithConfig(task.Context(), []TaskConfig{WithName(child.name)}, child.f, child.args...)
+
+		go func(t *Task, generation int) {
+			res, _ := t.Wait(0)
+			select {
+			case exits <- childExit{child: child, generation: generation, result: res}:
+			case <-done:
+			}
+		}(child.task, child.generation)
+	}
+
+	for _, child := range s.children {
+		start(child)
+	}
+
+	var restarts []time.Time
+	for {
+		select {
+		case <-task.Stopping():
+			return s.shutdown(nil, nil)
+		case exit := <-exits:
+			// Children exit when the supervisor's task is stopped too, so
+			// make sure they aren't restarted
+			select {
+			case <-task.Stopping():
+				return s.shutdown(nil, nil)
+			default:
+			}
+
+			children, unfinished := s.exited(exit)
+			if len(unfinished) > 0 {
+				// Children can't be restarted while their previous
+				// tasks are still running
+				return s.shutdown(nil, unfinished)
+			}
+			if len(children) == 0 {
+				continue
+			}
+
+			now := s.cfg.clock.Now()
+			restarts = append(pruneRestarts(restarts, now.Add(-s.policy.Window)), now)
+			if s.policy.MaxRestarts > 0 && len(restarts) > s.policy.MaxRestarts {
+				return s.shutdown(NewErrorResult(ErrRestartIntensity), nil)
+			}
+
+			if !s.wait(task, len(restarts)) {
+				return s.shutdown(nil, nil)
+			}
+
+			for _, child := range children {
+				start(child)
+			}
+		}
+	}
+}
+
+// exited handles a child exiting, stopping other children if the strategy
+// requires it, and returns the children that should be restarted along with
+// the tasks of stopped children that didn't finish in time.
+func (s *Supervisor) exited(exit childExit) ([]*supervisorChild, []*Task) {
+	s.lock.Lock()
+	// Exits from children the supervisor stopped itself are ignored
+	if exit.generation != exit.child.generation || exit.child.task == nil {
+		s.lock.Unlock()
+		return nil, nil
+	}
+	exit.child.task = nil
+	s.lock.Unlock()
+
+	switch exit.child.restart {
+	case RestartTemporary:
+		return nil, nil
+	case RestartTransient:
+		if exit.result == nil || exit.result.Err() == nil {
+			return nil, nil
+		}
+	}
+
+	var stopped []*supervisorChild
+	switch s.policy.Strategy {
+	case OneForAll:
+		stopped = s.children
+	case RestForOne:
+		for idx, child := range s.children {
+			if child == exit.child {
+				stopped = s.children[idx:]
+				break
+			}
+		}
+	default:
+		return []*supervisorChild{exit.child}, nil
+	}
+
+	unfinished := s.stop(stopped)
+
+	// Temporary children are never restarted, even if they were stopped
+	// because another child exited
+	children := make([]*supervisorChild, 0, len(stopped))
+	for _, child := range stopped {
+		if child == exit.child || child.restart != RestartTemporary {
+			children = append(children, child)
+		}
+	}
+	return children, unfinished
+}
+
+// shutdown stops every child and returns res, or a *ShutdownError result if
+// any children, including the already unfinished ones, didn't finish in time.
+func (s *Supervisor) shutdown(res TaskResult, unfinished []*Task) TaskResult {
+	unfinished = append(unfinished, s.stop(s.children)...)
+	if len(unfinished) > 0 {
+		return NewErrorResult(&ShutdownError{Tasks: unfinished})
+	}
+	return res
+}
+
+// stop stops the running children in the reverse order they were started and
+// waits for each of them to finish, returning the tasks of the children that
+// didn't finish within the policy's ShutdownTimeout.
+func (s *Supervisor) stop(children []*supervisorChild) []*Task {
+	var unfinished []*Task
+	for idx := len(children) - 1; idx >= 0; idx-- {
+		child := children[idx]
+
+		s.lock.Lock()
+		task := child.task
+		child.task = nil
+		s.lock.Unlock()
+
+		if task != nil {
+			task.cancelCtx()
+			if !s.waitFinished(task) {
+				unfinished = append(unfinished, task)
+			}
+		}
+	}
+	return unfinished
+}
+
+// waitFinished waits for a stopped child's task to finish. It returns false if
+// the task doesn't finish within the policy's ShutdownTimeout.
+func (s *Supervisor) waitFinished(task *Task) bool {
+	if s.policy.ShutdownTimeout <= 0 {
+		<-task.Finished()
+		return true
+	}
+
+	select {
+	case <-task.Finished():
+		return true
+	case <-s.cfg.clock.After(s.policy.ShutdownTimeout):
+		return false
+	}
+}
+
+// wait waits for the backoff before a restart. It returns false if task is
+// stopped while waiting.
+func (s *Supervisor) wait(task *Task, attempt int) bool {
+	var d time.Duration
+	if s.policy.Backoff != nil {
+		d = s.policy.Backoff(attempt)
+	}
+
+	if d <= 0 {
+		select {
+		case <-task.Stopping():
+			return false
+		default:
+			return true
+		}
+	}
+
+	select {
+	case <-s.cfg.clock.After(d):
+		return true
+	case <-task.Stopping():
+		return false
+	}
+}
+
+// pruneRestarts removes the restart times before since
+func pruneRestarts(restarts []time.Time, since time.Time) []time.Time {
+	idx := 0
+	for idx < len(restarts) && restarts[idx].Before(since) {
+		idx++
+	}
+	return restarts[idx:]
+}
@@ -0,0 +1,319 @@
+package boom
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/aphistic/sweet"
+	"github.com/efritz/glock"
+	. "github.com/onsi/gomega"
+)
+
+type SupervisorSuite struct{}
+
+// testChild is a long-running child that counts how many times it has been
+// started and exits with an error each time something is sent on crash.
+type testChild struct {
+	starts int32
+	crash  chan struct{}
+	exit   chan struct{}
+}
+
+func newTestChild() *testChild {
+	return &testChild{
+		crash: make(chan struct{}),
+		exit:  make(chan struct{}),
+	}
+}
+
+func (c *testChild) Run(task *Task, args ...interface{}) TaskResult {
+	atomic.AddInt32(&c.starts, 1)
+
+	select {
+	case <-c.crash:
+		return NewErrorResult(errors.New("crashed"))
+	case <-c.exit:
+		return nil
+	case <-task.Stopping():
+		return nil
+	}
+}
+
+func (c *testChild) Starts() int32 {
+	return atomic.LoadInt32(&c.starts)
+}
+
+func runSupervisor(sup *Supervisor, configs ...TaskConfig) *Task {
+	return NewTaskRunner(configs...).Run(sup.Run)
+}
+
+func (s *SupervisorSuite) TestOneForOne(t sweet.T) {
+	a, b := newTestChild(), newTestChild()
+	sup := NewSupervisor(SupervisorPolicy{Strategy: OneForOne})
+	Expect(sup.Add("a", a.Run)).To(BeNil())
+	Expect(sup.Add("b", b.Run)).To(BeNil())
+
+	task := runSupervisor(sup)
+	defer task.StopAndWait(0)
+
+	Eventually(a.Starts).Should(Equal(int32(1)))
+	Eventually(b.Starts).Should(Equal(int32(1)))
+	first := sup.Child("a")
+	Expect(first.Name()).To(Equal("a"))
+
+	a.crash <- struct{}{}
+	Eventually(a.Starts).Should(Equal(int32(2)))
+	Consistently(b.Starts).Should(Equal(int32(1)))
+	Expect(sup.Child("a")).ToNot(BeIdenticalTo(first))
+	Expect(sup.Child("missing")).To(BeNil())
+}
+
+func (s *SupervisorSuite) TestOneForAll(t sweet.T) {
+	a, b := newTestChild(), newTestChild()
+	sup := NewSupervisor(SupervisorPolicy{Strategy: OneForAll})
+	sup.Add("a", a.Run)
+	sup.Add("b", b.Run)
+
+	task := runSupervisor(sup)
+	defer task.StopAndWait(0)
+
+	Eventually(b.Starts).Should(Equal(int32(1)))
+
+	b.crash <- struct{}{}
+	Eventually(a.Starts).Should(Equal(int32(2)))
+	Eventually(b.Starts).Should(Equal(int32(2)))
+}
+
+func (s *SupervisorSuite) TestRestForOne(t sweet.T) {
+	a, b, c := newTestChild(), newTestChild(), newTestChild()
+	sup := NewSupervisor(SupervisorPolicy{Strategy: RestForOne})
+	sup.Add("a", a.Run)
+	sup.Add("b", b.Run)
+	sup.Add("c", c.Run)
+
+	task := runSupervisor(sup)
+	defer task.StopAndWait(0)
+
+	Eventually(c.Starts).Should(Equal(int32(1)))
+
+	b.crash <- struct{}{}
+	Eventually(b.Starts).Should(Equal(int32(2)))
+	Eventually(c.Starts).Should(Equal(int32(2)))
+	Consistently(a.Starts).Should(Equal(int32(1)))
+}
+
+func (s *SupervisorSuite) TestRestartTypes(t sweet.T) {
+	permanent, transient, temporary := newTestChild(), newTestChild(), newTestChild()
+	sup := NewSupervisor(SupervisorPolicy{Strategy: OneForOne})
+	sup.AddWithRestart("permanent", RestartPermanent, permanent.Run)
+	sup.AddWithRestart("transient", RestartTransient, transient.Run)
+	sup.AddWithRestart("temporary", RestartTemporary, temporary.Run)
+
+	task := runSupervisor(sup)
+	defer task.StopAndWait(0)
+
+	Eventually(temporary.Starts).Should(Equal(int32(1)))
+
+	permanent.exit <- struct{}{}
+	Eventually(permanent.Starts).Should(Equal(int32(2)))
+
+	transient.crash <- struct{}{}
+	Eventually(transient.Starts).Should(Equal(int32(2)))
+	transient.exit <- struct{}{}
+	Eventually(func() *Task { return sup.Child("transient") }).Should(BeNil())
+
+	temporary.crash <- struct{}{}
+	Eventually(func() *Task { return sup.Child("temporary") }).Should(BeNil())
+	Consistently(temporary.Starts).Should(Equal(int32(1)))
+}
+
+func (s *SupervisorSuite) TestOneForAllSkipsTemporary(t sweet.T) {
+	a, temporary := newTestChild(), newTestChild()
+	sup := NewSupervisor(SupervisorPolicy{Strategy: OneForAll})
+	sup.Add("a", a.Run)
+	sup.AddWithRestart("temporary", RestartTemporary, temporary.Run)
+
+	task := runSupervisor(sup)
+	defer task.StopAndWait(0)
+
+	Eventually(temporary.Starts).Should(Equal(int32(1)))
+
+	a.crash <- struct{}{}
+	Eventually(a.Starts).Should(Equal(int32(2)))
+	Consistently(temporary.Starts).Should(Equal(int32(1)))
+	Expect(sup.Child("temporary")).To(BeNil())
+}
+
+func (s *SupervisorSuite) TestRestartIntensity(t sweet.T) {
+	clock := glock.NewMockClock()
+	a := newTestChild()
+	sup := NewSupervisor(SupervisorPolicy{
+		Strategy:    OneForOne,
+		MaxRestarts: 2,
+		Window:      time.Minute,
+	}, WithClock(clock))
+	sup.Add("a", a.Run)
+
+	task := runSupervisor(sup, WithClock(clock))
+
+	a.crash <- struct{}{}
+	a.crash <- struct{}{}
+	Eventually(a.Starts).Should(Equal(int32(3)))
+
+	// Restarts outside of the window don't count
+	clock.Advance(2 * time.Minute)
+	a.crash <- struct{}{}
+	a.crash <- struct{}{}
+	Eventually(a.Starts).Should(Equal(int32(5)))
+
+	a.crash <- struct{}{}
+	res, err := task.Wait(0)
+	Expect(err).To(BeNil())
+	Expect(res.Err()).To(Equal(ErrRestartIntensity))
+	Expect(a.Starts()).To(Equal(int32(5)))
+	Expect(sup.Child("a")).To(BeNil())
+}
+
+func (s *SupervisorSuite) TestBackoff(t sweet.T) {
+	clock := glock.NewMockClock()
+	a := newTestChild()
+	sup := NewSupervisor(SupervisorPolicy{
+		Strategy: OneForOne,
+		Window:   time.Hour,
+		Backoff:  ExponentialBackoff(time.Second, 0),
+	}, WithClock(clock))
+	sup.Add("a", a.Run)
+
+	task := runSupervisor(sup, WithClock(clock))
+	defer task.StopAndWait(0)
+
+	a.crash <- struct{}{}
+	Consistently(a.Starts).Should(Equal(int32(1)))
+	clock.BlockingAdvance(time.Second)
+	Eventually(a.Starts).Should(Equal(int32(2)))
+
+	a.crash <- struct{}{}
+	clock.BlockingAdvance(time.Second)
+	Consistently(a.Starts).Should(Equal(int32(2)))
+	clock.Advance(time.Second)
+	Eventually(a.Starts).Should(Equal(int32(3)))
+}
+
+func (s *SupervisorSuite) TestStop(t sweet.T) {
+	a, b := newTestChild(), newTestChild()
+	sup := NewSupervisor(SupervisorPolicy{Strategy: OneForAll})
+	sup.Add("a", a.Run)
+	sup.Add("b", b.Run)
+
+	task := runSupervisor(sup)
+	Eventually(b.Starts).Should(Equal(int32(1)))
+	childA := sup.Child("a")
+
+	res, err := task.StopAndWait(0)
+	Expect(err).To(BeNil())
+	Expect(res).To(BeNil())
+	Expect(childA.Finished()).To(BeClosed())
+	Expect(a.Starts()).To(Equal(int32(1)))
+	Expect(b.Starts()).To(Equal(int32(1)))
+}
+
+// stuckChild ignores being stopped until release is closed
+func stuckChild(release <-chan struct{}) TaskFunc {
+	return func(task *Task, args ...interface{}) TaskResult {
+		<-release
+		return nil
+	}
+}
+
+func (s *SupervisorSuite) TestStopShutdownTimeout(t sweet.T) {
+	clock := glock.NewMockClock()
+	release := make(chan struct{})
+	defer close(release)
+
+	a := newTestChild()
+	sup := NewSupervisor(SupervisorPolicy{ShutdownTimeout: time.Second}, WithClock(clock))
+	sup.Add("a", a.Run)
+	sup.Add("stuck", stuckChild(release))
+
+	task := runSupervisor(sup)
+	Eventually(a.Starts).Should(Equal(int32(1)))
+	Eventually(func() *Task { return sup.Child("stuck") }).ShouldNot(BeNil())
+	stuck := sup.Child("stuck")
+
+	task.Stop()
+	clock.BlockingAdvance(time.Second)
+
+	res, err := task.Wait(0)
+	Expect(err).To(BeNil())
+	Expect(res.Err()).To(BeAssignableToTypeOf(&ShutdownError{}))
+	Expect(res.Err().(*ShutdownError).Tasks).To(ConsistOf(BeIdenticalTo(stuck)))
+}
+
+func (s *SupervisorSuite) TestRestartShutdownTimeout(t sweet.T) {
+	clock := glock.NewMockClock()
+	release := make(chan struct{})
+	defer close(release)
+
+	a := newTestChild()
+	sup := NewSupervisor(SupervisorPolicy{Strategy: OneForAll, ShutdownTimeout: time.Second}, WithClock(clock))
+	sup.Add("stuck", stuckChild(release))
+	sup.Add("a", a.Run)
+
+	task := runSupervisor(sup)
+	Eventually(a.Starts).Should(Equal(int32(1)))
+	stuck := sup.Child("stuck")
+
+	// The stuck child doesn't finish when it's stopped to restart every
+	// child, so the supervisor exits instead of restarting them
+	a.crash <- struct{}{}
+	clock.BlockingAdvance(time.Second)
+
+	res, err := task.Wait(0)
+	Expect(err).To(BeNil())
+	Expect(res.Err().(*ShutdownError).Tasks).To(ConsistOf(BeIdenticalTo(stuck)))
+	Consistently(a.Starts).Should(Equal(int32(1)))
+}
+
+func (s *SupervisorSuite) TestNested(t sweet.T) {
+	a := newTestChild()
+	inner := NewSupervisor(SupervisorPolicy{Strategy: OneForOne, MaxRestarts: 1, Window: time.Hour})
+	inner.Add("a", a.Run)
+
+	outer := NewSupervisor(SupervisorPolicy{Strategy: OneForOne})
+	outer.Add("inner", inner.Run)
+
+	task := runSupervisor(outer)
+	defer task.StopAndWait(0)
+
+	Eventually(a.Starts).Should(Equal(int32(1)))
+	firstInner := outer.Child("inner")
+
+	// The inner supervisor restarts its child once, then gives up and is
+	// restarted by the outer supervisor
+	a.crash <- struct{}{}
+	a.crash <- struct{}{}
+
+	Eventually(a.Starts).Should(Equal(int32(3)))
+	Eventually(firstInner.Finished()).Should(BeClosed())
+	res, _ := firstInner.Wait(0)
+	Expect(res.Err()).To(Equal(ErrRestartIntensity))
+	Expect(outer.Child("inner")).ToNot(BeNil())
+}
+
+func (s *SupervisorSuite) TestAddErrors(t sweet.T) {
+	a := newTestChild()
+	sup := NewSupervisor(SupervisorPolicy{})
+	Expect(sup.Add("a", a.Run)).To(BeNil())
+	Expect(sup.Add("a", a.Run)).To(Equal(ErrDuplicateChild))
+
+	task := runSupervisor(sup)
+	defer task.StopAndWait(0)
+	Eventually(a.Starts).Should(Equal(int32(1)))
+
+	Expect(sup.Add("b", a.Run)).To(Equal(ErrExecuting))
+
+	res, err := NewTaskRunner().Run(sup.Run).Wait(0)
+	Expect(err).To(BeNil())
+	Expect(res.Err()).To(Equal(ErrExecuting))
+}
@@ -0,0 +1,184 @@
+package boom
+
+import (
+	"context"
+	"sync"
+)
+
+// Span attribute keys set on task spans
+const (
+	SpanAttrTaskID   = "boom.task.id"
+	SpanAttrTaskName = "boom.task.name"
+	SpanAttrArgs     = "boom.task.args"
+	SpanAttrAttempts = "boom.task.attempts"
+)
+
+// defaultSpanName is the name of spans for tasks that don't have a name
+const defaultSpanName = "boom.task"
+
+// Tracer starts spans for tasks. It can be implemented to adapt a tracing
+// library, such as OpenTelemetry, to boom.
+type Tracer interface {
+	// StartSpan starts a new span as a child of the span in ctx, if there is
+	// one, and returns a context containing the new span.
+	StartSpan(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a span started by a Tracer.
+type Span interface {
+	// SetAttribute sets an attribute on the span
+	SetAttribute(key string, value interface{})
+	// SetError records an error on the span
+	SetError(err error)
+	// End ends the span
+	End()
+}
+
+// WithTracer starts a span for each task when it starts, as a child of the span
+// in the task's context. The task's context contains the task's span while it
+// executes, so spans started from it are children of the task's span. The span
+// is ended when the task finishes.
+func WithTracer(tracer Tracer) TaskConfig {
+	return func(cfg *taskConfig) {
+		cfg.tracer = tracer
+	}
+}
+
+// startSpan starts the task's span if the task has a tracer
+func (t *Task) startSpan() Span {
+	if t.cfg.tracer == nil {
+		return nil
+	}
+
+	name := t.name
+	if name == "" {
+		name = defaultSpanName
+	}
+
+	ctx, span := t.cfg.tracer.StartSpan(t.ctx.Context, name)
+	t.ctx.setValues(ctx)
+
+	span.SetAttribute(SpanAttrTaskID, t.id)
+	span.SetAttribute(SpanAttrTaskName, t.name)
+	span.SetAttribute(SpanAttrArgs, len(t.args))
+
+	return span
+}
+
+// endSpan records the task's result on its span and ends it
+func (t *Task) endSpan(span Span, res TaskResult) {
+	if span == nil {
+		return
+	}
+
+	span.SetAttribute(SpanAttrAttempts, t.Attempts())
+	if res != nil && res.Err() != nil {
+		span.SetError(res.Err())
+	}
+	span.End()
+}
+
+// InMemoryTracer is a Tracer that keeps finished spans in memory, which is
+// useful for testing.
+type InMemoryTracer struct {
+	lock   sync.Mutex
+	lastID uint64
+	spans  []RecordedSpan
+}
+
+// RecordedSpan is a finished span recorded by an InMemoryTracer
+type RecordedSpan struct {
+	ID         uint64
+	ParentID   uint64
+	Name       string
+	Attributes map[string]interface{}
+	Err        error
+}
+
+type inMemorySpanKey struct{}
+
+type inMemorySpan struct {
+	tracer *InMemoryTracer
+	ended  bool
+	span   RecordedSpan
+}
+
+// NewInMemoryTracer creates a new InMemoryTracer
+func NewInMemoryTracer() *InMemoryTracer {
+	return &InMemoryTracer{}
+}
+
+// StartSpan implements Tracer
+func (t *InMemoryTracer) StartSpan(ctx context.Context, name string) (context.Context, Span) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.lastID++
+	span := &inMemorySpan{
+		tracer: t,
+		span: RecordedSpan{
+			ID:         t.lastID,
+			Name:       name,
+			Attributes: make(map[string]interface{}),
+		},
+	}
+
+	if parent, ok := ctx.Value(inMemorySpanKey{}).(*inMemorySpan); ok {
+		span.span.ParentID = parent.span.ID
+	}
+
+	return context.WithValue(ctx, inMemorySpanKey{}, span), span
+}
+
+// Spans returns the spans that have ended, in the order they ended
+func (t *InMemoryTracer) Spans() []RecordedSpan {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return append([]RecordedSpan{}, t.spans...)
+}
+
+// Reset removes all recorded spans
+func (t *InMemoryTracer) Reset() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.spans = nil
+}
+
+func (s *inMemorySpan) SetAttribute(key string, value interface{}) {
+	s.tracer.lock.Lock()
+	defer s.tracer.lock.Unlock()
+
+	if !s.ended {
+		s.span.Attributes[key] = value
+	}
+}
+
+func (s *inMemorySpan) SetError(err error) {
+	s.tracer.lock.Lock()
+	defer s.tracer.lock.Unlock()
+
+	if !s.ended {
+		s.span.Err = err
+	}
+}
+
+func (s *inMemorySpan) End() {
+	s.tracer.lock.Lock()
+	defer s.tracer.lock.Unlock()
+
+	if s.ended {
+		return
+	}
+	s.ended = true
+
+	attrs := make(map[string]interface{}, len(s.span.Attributes))
+	for key, value := range s.span.Attributes {
+		attrs[key] = value
+	}
+	span := s.span
+	span.Attributes = attrs
+
+	s.tracer.spans = append(s.tracer.spans, span)
+}
@@ -0,0 +1,137 @@
+package boom
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aphistic/sweet"
+	"github.com/efritz/glock"
+	. "github.com/onsi/gomega"
+)
+
+type TracingSuite struct{}
+
+func (s *TracingSuite) TestTaskSpan(t sweet.T) {
+	tracer := NewInMemoryTracer()
+	tr := NewTaskRunner(WithTracer(tracer), WithName("traced"))
+
+	task := tr.Run(func(task *Task, args ...interface{}) TaskResult {
+		return NewValueResult(len(args), nil)
+	}, 1, 2, 3)
+	task.Wait(0)
+
+	spans := tracer.Spans()
+	Expect(spans).To(HaveLen(1))
+	Expect(spans[0].Name).To(Equal("traced"))
+	Expect(spans[0].ParentID).To(Equal(uint64(0)))
+	Expect(spans[0].Err).To(BeNil())
+	Expect(spans[0].Attributes).To(Equal(map[string]interface{}{
+		SpanAttrTaskID:   task.ID(),
+		SpanAttrTaskName: "traced",
+		SpanAttrArgs:     3,
+		SpanAttrAttempts: 1,
+	}))
+}
+
+func (s *TracingSuite) TestDefaultSpanName(t sweet.T) {
+	tracer := NewInMemoryTracer()
+	tr := NewTaskRunner(WithTracer(tracer))
+
+	tr.Run(func(task *Task, args ...interface{}) TaskResult {
+		return nil
+	}).Wait(0)
+
+	Expect(tracer.Spans()).To(HaveLen(1))
+	Expect(tracer.Spans()[0].Name).To(Equal("boom.task"))
+}
+
+func (s *TracingSuite) TestParentSpan(t sweet.T) {
+	tracer := NewInMemoryTracer()
+	tr := NewTaskRunner(WithTracer(tracer))
+
+	ctx, parent := tracer.StartSpan(context.Background(), "parent")
+	ctx = context.WithValue(ctx, "key", "value")
+
+	tr.RunWithContext(ctx, func(task *Task, args ...interface{}) TaskResult {
+		Expect(task.Context().Value("key")).To(Equal("value"))
+
+		_, child := tracer.StartSpan(task.Context(), "child")
+		child.End()
+		return nil
+	}).Wait(0)
+	parent.End()
+
+	spans := tracer.Spans()
+	Expect(spans).To(HaveLen(3))
+	Expect(spans[0].Name).To(Equal("child"))
+	Expect(spans[1].Name).To(Equal("boom.task"))
+	Expect(spans[2].Name).To(Equal("parent"))
+
+	Expect(spans[0].ParentID).To(Equal(spans[1].ID))
+	Expect(spans[1].ParentID).To(Equal(spans[2].ID))
+}
+
+func (s *TracingSuite) TestSpanError(t sweet.T) {
+	tracer := NewInMemoryTracer()
+	tr := NewTaskRunner(WithTracer(tracer))
+
+	tr.Run(func(task *Task, args ...interface{}) TaskResult {
+		return NewErrorResult(errors.New("failed"))
+	}).Wait(0)
+	tr.Run(func(task *Task, args ...interface{}) TaskResult {
+		panic("oops")
+	}).Wait(0)
+
+	spans := tracer.Spans()
+	Expect(spans).To(HaveLen(2))
+	Expect(spans[0].Err).To(MatchError("failed"))
+	Expect(spans[1].Err).To(Equal(ErrPanic))
+}
+
+func (s *TracingSuite) TestSpanAttempts(t sweet.T) {
+	tracer := NewInMemoryTracer()
+	tr := NewTaskRunner(
+		WithClock(glock.NewMockClock()),
+		WithTracer(tracer),
+		WithRetry(RetryPolicy{MaxAttempts: 3}),
+	)
+
+	tr.Run(func(task *Task, args ...interface{}) TaskResult {
+		return NewErrorResult(errors.New("failed"))
+	}).Wait(0)
+
+	spans := tracer.Spans()
+	Expect(spans).To(HaveLen(1))
+	Expect(spans[0].Attributes[SpanAttrAttempts]).To(Equal(3))
+}
+
+func (s *TracingSuite) TestCollectorSpans(t sweet.T) {
+	tracer := NewInMemoryTracer()
+	c := NewAsyncCollector(WithTracer(tracer), WithName("collected"))
+
+	for i := 0; i < 3; i++ {
+		c.Run(func(task *Task, args ...interface{}) TaskResult {
+			return nil
+		})
+	}
+
+	_, err := c.Wait(time.Second)
+	Expect(err).To(BeNil())
+	Expect(tracer.Spans()).To(HaveLen(3))
+
+	tracer.Reset()
+	Expect(tracer.Spans()).To(BeEmpty())
+}
+
+func (s *TracingSuite) TestNoSpanWithoutStart(t sweet.T) {
+	tracer := NewInMemoryTracer()
+	tr := NewTaskRunner(WithTracer(tracer))
+
+	task := tr.New(func(task *Task, args ...interface{}) TaskResult {
+		return nil
+	})
+	task.Stop()
+
+	Expect(tracer.Spans()).To(BeEmpty())
+}
@@ -0,0 +1,158 @@
+//go:build go1.18
+// +build go1.18
+
+package boom
+
+import (
+	"context"
+	"time"
+)
+
+// TypedTaskFunc is the signature for a function executed to perform a task that
+// produces a value of type T.
+type TypedTaskFunc[T any] func(task *Task) (T, error)
+
+// TypedResult is a TaskResult holding a value of type T.
+type TypedResult[T any] struct {
+	Value T
+	Error error
+}
+
+// NewTypedResult is a convenience function for creating a TypedResult
+func NewTypedResult[T any](val T, err error) *TypedResult[T] {
+	return &TypedResult[T]{
+		Value: val,
+		Error: err,
+	}
+}
+
+func (r *TypedResult[T]) Err() error {
+	return r.Error
+}
+
+// Typed converts a TypedTaskFunc into a TaskFunc that returns a *TypedResult[T],
+// so it can be used anywhere a TaskFunc is accepted.
+func Typed[T any](f TypedTaskFunc[T]) TaskFunc {
+	return func(task *Task, args ...interface{}) TaskResult {
+		val, err := f(task)
+		return NewTypedResult(val, err)
+	}
+}
+
+// ResultValue returns the value and error held by a TaskResult. The result may be
+// a *TypedResult[T] or a *ValueResult holding a T. If the result holds a value of
+// another type and has no error, ErrResultType is returned.
+func ResultValue[T any](res TaskResult) (T, error) {
+	var zero T
+
+	switch r := res.(type) {
+	case nil:
+		return zero, nil
+	case *TypedResult[T]:
+		return r.Value, r.Error
+	case *ValueResult:
+		if val, ok := r.Value.(T); ok {
+			return val, r.Error
+		}
+	}
+
+	if err := res.Err(); err != nil {
+		return zero, err
+	}
+	return zero, ErrResultType
+}
+
+// TypedTask is a Task whose result holds a value of type T.
+type TypedTask[T any] struct {
+	*Task
+}
+
+// NewTyped creates a new typed task with the given runner and function
+func NewTyped[T any](runner *TaskRunner, f TypedTaskFunc[T]) *TypedTask[T] {
+	return &TypedTask[T]{
+		Task: runner.New(Typed(f)),
+	}
+}
+
+// RunTyped creates a new typed task with the given runner and function and
+// immediately starts executing it.
+func RunTyped[T any](runner *TaskRunner, f TypedTaskFunc[T]) *TypedTask[T] {
+	return &TypedTask[T]{
+		Task: runner.Run(Typed(f)),
+	}
+}
+
+// RunTypedWithContext calls RunTyped using the provided context.Context for the task
+func RunTypedWithContext[T any](ctx context.Context, runner *TaskRunner, f TypedTaskFunc[T]) *TypedTask[T] {
+	return &TypedTask[T]{
+		Task: runner.RunWithContext(ctx, Typed(f)),
+	}
+}
+
+// WaitValue calls Wait on the task and returns the value and error from its result.
+func (t *TypedTask[T]) WaitValue(timeout time.Duration) (T, error) {
+	res, err := t.Wait(timeout)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return ResultValue[T](res)
+}
+
+// TypedCollector is an AsyncCollector for tasks that produce values of type T.
+type TypedCollector[T any] struct {
+	col *AsyncCollector
+}
+
+// NewTypedCollector creates a new TypedCollector instance
+func NewTypedCollector[T any](configs ...TaskConfig) *TypedCollector[T] {
+	return &TypedCollector[T]{
+		col: NewAsyncCollector(configs...),
+	}
+}
+
+// Collector returns the AsyncCollector the typed collector runs its tasks with.
+func (c *TypedCollector[T]) Collector() *AsyncCollector {
+	return c.col
+}
+
+// Run takes a TypedTaskFunc to execute and immediately starts executing it.
+func (c *TypedCollector[T]) Run(f TypedTaskFunc[T]) {
+	c.col.Run(Typed(f))
+}
+
+// RunWithContext calls Run and uses the provided context.Context to run the task.
+func (c *TypedCollector[T]) RunWithContext(ctx context.Context, f TypedTaskFunc[T]) {
+	c.col.RunWithContext(ctx, Typed(f))
+}
+
+// Wait will wait until all tasks have finished, similar to AsyncCollector.Wait, and
+// return the value produced by each task. If any tasks failed, their values are left
+// as the zero value and a TaskErrors is returned containing their errors.
+func (c *TypedCollector[T]) Wait(timeout time.Duration) ([]T, error) {
+	return c.WaitCloser(timeout, nil)
+}
+
+// WaitCloser will wait similar to Wait, calling closer as AsyncCollector.WaitCloser does.
+func (c *TypedCollector[T]) WaitCloser(timeout time.Duration, closer CollectorCloser) ([]T, error) {
+	results, err := c.col.WaitCloser(timeout, closer)
+	if err != nil {
+		return nil, err
+	}
+
+	vals := make([]T, len(results))
+	errs := TaskErrors{}
+	for idx, res := range results {
+		val, err := ResultValue[T](res)
+		if err != nil {
+			errs[idx] = err
+			continue
+		}
+		vals[idx] = val
+	}
+
+	if len(errs) > 0 {
+		return vals, errs
+	}
+	return vals, nil
+}
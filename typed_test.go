@@ -0,0 +1,101 @@
+//go:build go1.18
+// +build go1.18
+
+package boom
+
+import (
+	"errors"
+	"time"
+
+	"github.com/aphistic/sweet"
+	. "github.com/onsi/gomega"
+)
+
+type TypedSuite struct{}
+
+func init() {
+	versionedSuites = append(versionedSuites, &TypedSuite{})
+}
+
+func (s *TypedSuite) TestRunTyped(t sweet.T) {
+	tr := NewTaskRunner()
+	task := RunTyped(tr, func(task *Task) (string, error) {
+		return "value", nil
+	})
+
+	val, err := task.WaitValue(time.Second)
+	Expect(err).To(BeNil())
+	Expect(val).To(Equal("value"))
+
+	res, err := task.Wait(time.Second)
+	Expect(err).To(BeNil())
+	Expect(res).To(Equal(NewTypedResult("value", nil)))
+}
+
+func (s *TypedSuite) TestRunTypedError(t sweet.T) {
+	tr := NewTaskRunner()
+	task := RunTyped(tr, func(task *Task) (int, error) {
+		return 5, errors.New("I'm an error! - Ralph")
+	})
+
+	val, err := task.WaitValue(time.Second)
+	Expect(err).To(Equal(errors.New("I'm an error! - Ralph")))
+	Expect(val).To(Equal(5))
+}
+
+func (s *TypedSuite) TestResultValue(t sweet.T) {
+	val, err := ResultValue[int](NewValueResult(1, nil))
+	Expect(err).To(BeNil())
+	Expect(val).To(Equal(1))
+
+	val, err = ResultValue[int](NewValueResult("1", nil))
+	Expect(err).To(Equal(ErrResultType))
+	Expect(val).To(Equal(0))
+
+	val, err = ResultValue[int](NewErrorResult(errors.New("I'm an error! - Ralph")))
+	Expect(err).To(Equal(errors.New("I'm an error! - Ralph")))
+	Expect(val).To(Equal(0))
+
+	val, err = ResultValue[int](nil)
+	Expect(err).To(BeNil())
+	Expect(val).To(Equal(0))
+}
+
+func (s *TypedSuite) TestTypedCollector(t sweet.T) {
+	col := NewTypedCollector[int]()
+
+	// The first task finishes after the second so the values have to be
+	// ordered by task rather than by when they finished
+	second := make(chan struct{})
+	col.Run(func(task *Task) (int, error) {
+		<-second
+		return 1, nil
+	})
+	col.Run(func(task *Task) (int, error) {
+		defer close(second)
+		return 2, nil
+	})
+	col.Collector().Run(func(task *Task, args ...interface{}) TaskResult {
+		return NewValueResult(3, nil)
+	})
+
+	vals, err := col.Wait(time.Second)
+	Expect(err).To(BeNil())
+	Expect(vals).To(Equal([]int{1, 2, 3}))
+}
+
+func (s *TypedSuite) TestTypedCollectorErrors(t sweet.T) {
+	col := NewTypedCollector[int]()
+
+	col.Run(func(task *Task) (int, error) {
+		return 1, nil
+	})
+	col.Run(func(task *Task) (int, error) {
+		return 2, errors.New("I'm an error! - Ralph")
+	})
+
+	vals, err := col.Wait(time.Second)
+	Expect(vals).To(Equal([]int{1, 0}))
+	Expect(err).To(Equal(TaskErrors{1: errors.New("I'm an error! - Ralph")}))
+	Expect(err.Error()).To(Equal("task 1: I'm an error! - Ralph"))
+}